@@ -0,0 +1,383 @@
+// Buildergen generates fluent builders for struct types.
+//
+// Usage:
+//
+//	//go:generate go run github.com/Tamplier2911/gof-design-patterns/golang/cmd/buildergen -type Customer
+//
+// Builder is configured with `builder` struct tags:
+//
+//	required      - Build reports an error when field is left zero
+//	default=value - value assigned before any modifier, only basic types are supported (no commas)
+//	facet=name    - field is set through a facet builder, e.g. EmployeeBuilder.Address()
+//	-             - field is skipped
+//
+// Types without facets get a functional builder in the style of builder.PersonBuilder,
+// types with facets get a faceted builder in the style of builder.EmployeeBuilder.
+// Both support validation hooks through Validate(func(*T) error).
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("buildergen: ")
+
+	typeName := flag.String("type", "", "struct type name to generate builder for (required)")
+	file := flag.String("file", os.Getenv("GOFILE"), "go source file declaring the type")
+	output := flag.String("output", "", "output file name, defaults to <type>_builder.go")
+	tests := flag.Bool("tests", false, "also generate <type>_builder_test.go")
+	flag.Parse()
+
+	if *typeName == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := parseSpec(*file, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		*output = filepath.Join(filepath.Dir(*file), strings.ToLower(*typeName)+"_builder.go")
+	}
+	if err := generate(*output, builderTemplate, spec); err != nil {
+		log.Fatal(err)
+	}
+	if *tests {
+		testOutput := strings.TrimSuffix(*output, ".go") + "_test.go"
+		if err := generate(testOutput, testTemplate, spec); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// -- Spec
+
+// Spec - represents struct type builder is generated for.
+type Spec struct {
+	Package string
+	Type    string
+	Imports []string
+	Fields  []*Field
+	Facets  []*Facet
+}
+
+// Field - represents struct field and its builder options.
+type Field struct {
+	Name     string
+	Method   string
+	Type     string
+	Kind     string // basic type name, empty for composite types
+	Required bool
+	Default  string // go literal
+	Facet    string
+}
+
+// Facet - represents group of fields set through separate builder.
+type Facet struct {
+	Name   string
+	Method string
+	Fields []*Field
+}
+
+// Faceted - indicates if faceted builder should be generated.
+func (s *Spec) Faceted() bool {
+	return len(s.Facets) > 0
+}
+
+// Var - returns lower camel case type name used for unexported identifiers.
+func (s *Spec) Var() string {
+	return lowerFirst(s.Type)
+}
+
+// RootFields - returns fields which are not part of any facet.
+func (s *Spec) RootFields() []*Field {
+	var fields []*Field
+	for _, f := range s.Fields {
+		if f.Facet == "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Required - returns required fields.
+func (s *Spec) Required() []*Field {
+	var fields []*Field
+	for _, f := range s.Fields {
+		if f.Required {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Defaults - returns fields with default values.
+func (s *Spec) Defaults() []*Field {
+	var fields []*Field
+	for _, f := range s.Fields {
+		if f.Default != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Testable - indicates if every required field can be filled with sample value in tests.
+func (s *Spec) Testable() bool {
+	for _, f := range s.Required() {
+		if f.Sample() == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Sample - returns non-zero literal of basic field type, empty for composite types.
+func (f *Field) Sample() string {
+	switch {
+	case f.Kind == "string":
+		return strconv.Quote("sample " + f.Name)
+	case f.Kind == "bool":
+		return "true"
+	case f.Kind == "":
+		return ""
+	default:
+		return "1"
+	}
+}
+
+// Setter - returns expression used in tests to reach field setter from builder variable b.
+func (f *Field) Setter() string {
+	if f.Facet == "" {
+		return "b." + f.Method
+	}
+	return "b." + exported(f.Facet) + "()." + f.Method
+}
+
+// parseSpec - parses source file and collects builder spec of provided struct type.
+func parseSpec(file, typeName string) (*Spec, error) {
+	fset := token.NewFileSet()
+	af, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var st *ast.StructType
+	ast.Inspect(af, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+			st, _ = ts.Type.(*ast.StructType)
+			return false
+		}
+		return st == nil
+	})
+	if st == nil {
+		return nil, fmt.Errorf("struct type %s not found in %s", typeName, file)
+	}
+
+	spec := &Spec{Package: af.Name.Name, Type: typeName}
+	used := map[string]bool{}
+	facets := map[string]*Facet{}
+	for _, fl := range st.Fields.List {
+		if len(fl.Names) == 0 {
+			continue // embedded fields are not supported
+		}
+		var tag string
+		if fl.Tag != nil {
+			raw, _ := strconv.Unquote(fl.Tag.Value)
+			tag = reflect.StructTag(raw).Get("builder")
+		}
+		if tag == "-" {
+			continue
+		}
+		typ := exprString(fset, fl.Type)
+		collectPackages(fl.Type, used)
+		for _, name := range fl.Names {
+			f, err := parseField(name.Name, typ, fl.Type, tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", typeName, name.Name, err)
+			}
+			spec.Fields = append(spec.Fields, f)
+			if f.Facet == "" {
+				continue
+			}
+			if _, ok := facets[f.Facet]; !ok {
+				facets[f.Facet] = &Facet{Name: f.Facet, Method: exported(f.Facet)}
+				spec.Facets = append(spec.Facets, facets[f.Facet])
+			}
+			facets[f.Facet].Fields = append(facets[f.Facet].Fields, f)
+		}
+	}
+
+	// keep imports referenced by field types
+	for _, imp := range af.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		decl := imp.Path.Value
+		if imp.Name != nil {
+			name = imp.Name.Name
+			decl = imp.Name.Name + " " + decl
+		}
+		if used[name] {
+			spec.Imports = append(spec.Imports, decl)
+		}
+	}
+
+	return spec, checkNames(spec)
+}
+
+// parseField - creates field from its declaration and builder tag.
+func parseField(name, typ string, expr ast.Expr, tag string) (*Field, error) {
+	f := &Field{Name: name, Method: exported(name), Type: typ}
+	if id, ok := expr.(*ast.Ident); ok && basicKinds[id.Name] {
+		f.Kind = id.Name
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "":
+		case opt == "required":
+			f.Required = true
+		case strings.HasPrefix(opt, "default="):
+			lit, err := defaultLiteral(f.Kind, strings.TrimPrefix(opt, "default="))
+			if err != nil {
+				return nil, err
+			}
+			f.Default = lit
+		case strings.HasPrefix(opt, "facet="):
+			f.Facet = strings.TrimPrefix(opt, "facet=")
+			if !token.IsIdentifier(f.Facet) {
+				return nil, fmt.Errorf("invalid facet name %q", f.Facet)
+			}
+		default:
+			return nil, fmt.Errorf("unknown builder option %q", opt)
+		}
+	}
+	return f, nil
+}
+
+// basicKinds - types default values can be provided for.
+var basicKinds = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "byte": true, "rune": true,
+}
+
+// defaultLiteral - converts tag default value into go literal of provided kind.
+func defaultLiteral(kind, value string) (string, error) {
+	var err error
+	switch {
+	case kind == "":
+		return "", errors.New("default value is supported only for basic types")
+	case kind == "string":
+		return strconv.Quote(value), nil
+	case kind == "bool":
+		_, err = strconv.ParseBool(value)
+	case strings.HasPrefix(kind, "float"):
+		_, err = strconv.ParseFloat(value, 64)
+	case strings.HasPrefix(kind, "uint") || kind == "byte":
+		_, err = strconv.ParseUint(value, 0, 64)
+	default:
+		_, err = strconv.ParseInt(value, 0, 64)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid %s default %q", kind, value)
+	}
+	return value, nil
+}
+
+// checkNames - makes sure generated methods don't collide.
+func checkNames(spec *Spec) error {
+	root := map[string]string{"Build": "builder", "Validate": "builder"}
+	for _, f := range spec.RootFields() {
+		if prev, ok := root[f.Method]; ok {
+			return fmt.Errorf("method %s of field %s collides with %s", f.Method, f.Name, prev)
+		}
+		root[f.Method] = "field " + f.Name
+	}
+	for _, fc := range spec.Facets {
+		if prev, ok := root[fc.Method]; ok {
+			return fmt.Errorf("method %s of facet %s collides with %s", fc.Method, fc.Name, prev)
+		}
+		root[fc.Method] = "facet " + fc.Name
+	}
+	for _, fc := range spec.Facets {
+		own := map[string]bool{}
+		for _, f := range fc.Fields {
+			if own[f.Method] || root[f.Method] != "" {
+				return fmt.Errorf("method %s of field %s collides within facet %s", f.Method, f.Name, fc.Name)
+			}
+			own[f.Method] = true
+		}
+	}
+	return nil
+}
+
+// -- Helpers
+
+// exprString - formats type expression as source code.
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	buff := new(bytes.Buffer)
+	_ = format.Node(buff, fset, expr)
+	return buff.String()
+}
+
+// collectPackages - records package names referenced by type expression.
+func collectPackages(expr ast.Expr, used map[string]bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+}
+
+// exported - upper cases first letter.
+func exported(s string) string {
+	rs := []rune(s)
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+// lowerFirst - lower cases first letter.
+func lowerFirst(s string) string {
+	rs := []rune(s)
+	rs[0] = unicode.ToLower(rs[0])
+	return string(rs)
+}
+
+// generate - executes template with spec, formats result and writes it to file.
+func generate(output string, tmpl *template.Template, spec *Spec) error {
+	buff := new(bytes.Buffer)
+	if err := tmpl.Execute(buff, spec); err != nil {
+		return err
+	}
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting %s: %w\n%s", output, err, buff.String())
+	}
+	return ioutil.WriteFile(output, src, 0o644)
+}
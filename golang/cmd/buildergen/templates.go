@@ -0,0 +1,210 @@
+package main
+
+import "text/template"
+
+// builderTemplate - generates functional or faceted builder.
+var builderTemplate = template.Must(template.New("builder").Parse(`// Code generated by buildergen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	{{- if .Required}}
+	"reflect"
+	{{- end}}
+	"strings"
+	{{- range .Imports}}
+	{{.}}
+	{{- end}}
+)
+
+{{if .Faceted -}}
+// -- Faceted Builder
+
+// {{.Type}}Builder - represents {{.Type}} builder.
+type {{.Type}}Builder struct {
+	v          *{{.Type}}
+	validators *[]func(v *{{.Type}}) error // shared between facets
+}
+
+// New{{.Type}}Builder - creates new instance of {{.Type}}Builder.
+func New{{.Type}}Builder() *{{.Type}}Builder {
+	return &{{.Type}}Builder{
+		v: &{{.Type}}{
+			{{- range .Defaults}}
+			{{.Name}}: {{.Default}},
+			{{- end}}
+		},
+		validators: &[]func(v *{{.Type}}) error{},
+	}
+}
+{{range .RootFields}}
+// {{.Method}} - sets {{$.Type}} {{.Name}} field.
+func (b *{{$.Type}}Builder) {{.Method}}(v {{.Type}}) *{{$.Type}}Builder {
+	b.v.{{.Name}} = v
+	return b
+}
+{{end}}
+{{- range .Facets}}
+// {{.Method}} - grants access to methods required to construct {{$.Type}} {{.Name}} details.
+func (b *{{$.Type}}Builder) {{.Method}}() *{{$.Type}}{{.Method}}Builder {
+	return New{{$.Type}}{{.Method}}Builder(b)
+}
+{{end}}
+// Validate - appends validation hook run on Build.
+func (b *{{.Type}}Builder) Validate(fn func(v *{{.Type}}) error) *{{.Type}}Builder {
+	*b.validators = append(*b.validators, fn)
+	return b
+}
+
+// Build - returns instance of built {{.Type}}, validates it.
+func (b *{{.Type}}Builder) Build() (*{{.Type}}, error) {
+	if err := validate{{.Type}}(b.v, *b.validators); err != nil {
+		return nil, err
+	}
+	return b.v, nil
+}
+{{range $facet := .Facets}}
+// {{$.Type}}{{.Method}}Builder - represents {{$.Type}} {{.Name}} builder.
+type {{$.Type}}{{.Method}}Builder struct {
+	{{$.Type}}Builder
+}
+
+// New{{$.Type}}{{.Method}}Builder - creates new instance of {{$.Type}}{{.Method}}Builder.
+func New{{$.Type}}{{.Method}}Builder(b *{{$.Type}}Builder) *{{$.Type}}{{.Method}}Builder {
+	return &{{$.Type}}{{.Method}}Builder{*b}
+}
+{{range .Fields}}
+// {{.Method}} - sets {{$.Type}} {{.Name}} field.
+func (b *{{$.Type}}{{$facet.Method}}Builder) {{.Method}}(v {{.Type}}) *{{$.Type}}{{$facet.Method}}Builder {
+	b.v.{{.Name}} = v
+	return b
+}
+{{end}}
+{{- end}}
+{{- else -}}
+// -- Functional Builder
+
+// {{.Var}}Mod - represents {{.Type}} modifier.
+type {{.Var}}Mod func(v *{{.Type}})
+
+// {{.Type}}Builder - represents {{.Type}} builder.
+type {{.Type}}Builder struct {
+	actions    []{{.Var}}Mod
+	validators []func(v *{{.Type}}) error
+}
+
+// New{{.Type}}Builder - creates new instance of {{.Type}}Builder.
+func New{{.Type}}Builder() *{{.Type}}Builder {
+	return &{{.Type}}Builder{}
+}
+{{range .Fields}}
+// {{.Method}} - creates {{$.Type}} {{.Name}} modifier and appends it to action list.
+func (b *{{$.Type}}Builder) {{.Method}}(v {{.Type}}) *{{$.Type}}Builder {
+	b.actions = append(b.actions, func(x *{{$.Type}}) {
+		x.{{.Name}} = v
+	})
+	return b
+}
+{{end}}
+// Validate - appends validation hook run on Build.
+func (b *{{.Type}}Builder) Validate(fn func(v *{{.Type}}) error) *{{.Type}}Builder {
+	b.validators = append(b.validators, fn)
+	return b
+}
+
+// Build - builds {{.Type}} object based on defined actions, validates it.
+func (b *{{.Type}}Builder) Build() (*{{.Type}}, error) {
+	v := &{{.Type}}{
+		{{- range .Defaults}}
+		{{.Name}}: {{.Default}},
+		{{- end}}
+	}
+	for _, action := range b.actions {
+		action(v)
+	}
+	if err := validate{{.Type}}(v, b.validators); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+{{end}}
+// validate{{.Type}} - checks required {{.Type}} fields and runs validation hooks, accumulates every problem.
+func validate{{.Type}}(v *{{.Type}}, validators []func(v *{{.Type}}) error) error {
+	var msgs []string
+	{{- range .Required}}
+	if reflect.ValueOf(v.{{.Name}}).IsZero() {
+		msgs = append(msgs, "{{.Name}}: is required")
+	}
+	{{- end}}
+	for _, fn := range validators {
+		if err := fn(v); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("invalid {{.Type}}: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+`))
+
+// testTemplate - generates tests of generated builder.
+var testTemplate = template.Must(template.New("test").Parse(`// Code generated by buildergen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	{{- if .Testable}}
+	"errors"
+	{{- end}}
+	"strings"
+	"testing"
+)
+{{if .Required}}
+func Test{{.Type}}BuilderRequired(t *testing.T) {
+	_, err := New{{.Type}}Builder().Build()
+	if err == nil {
+		t.Fatal("expected error for missing required fields")
+	}
+	for _, field := range []string{ {{- range .Required}}"{{.Name}}", {{end -}} } {
+		if !strings.Contains(err.Error(), field+": is required") {
+			t.Errorf("expected %s to be reported, got %v", field, err)
+		}
+	}
+}
+{{end}}
+{{- if .Testable}}
+func Test{{.Type}}BuilderDefaults(t *testing.T) {
+	b := New{{.Type}}Builder()
+	{{- range .Required}}
+	{{.Setter}}({{.Sample}})
+	{{- end}}
+	v, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	{{- range .Defaults}}
+	{{- if not .Required}}
+	if v.{{.Name}} != {{.Default}} {
+		t.Errorf("expected {{.Name}} to be defaulted, got %v", v.{{.Name}})
+	}
+	{{- end}}
+	{{- end}}
+	_ = v
+}
+
+func Test{{.Type}}BuilderValidate(t *testing.T) {
+	b := New{{.Type}}Builder()
+	{{- range .Required}}
+	{{.Setter}}({{.Sample}})
+	{{- end}}
+	_, err := b.Validate(func(v *{{.Type}}) error {
+		return errors.New("hook failed")
+	}).Build()
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Fatalf("expected validation hook error, got %v", err)
+	}
+}
+{{end -}}
+`))
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Builder: when piecewise object construction is complicated provides an API for doing it succinctly.
@@ -33,25 +34,65 @@ func Builder() {
 	fmt.Printf("Query: %s Result: %s \n", qb.Query, userName)
 
 	// builder parameter
-	SendEmailBuilderParameter(func(b *EmailBuilder) {
+	err = SendEmailBuilderParameter(func(b *EmailBuilder) {
 		// email object should not be accessible from different module
 		b.
-			From("foo@email.com").
-			To("bar@email.com").
-			Subject("sub").
-			Body("body")
+			From("Foo <foo@email.com>").
+			To("bar@email.com", "Baz <baz@email.com>").
+			Cc("qux@email.com").
+			Bcc("audit@email.com").
+			ReplyTo("noreply@email.com").
+			Header("X-Priority", "3").
+			Subject("Sujet – café").
+			Body("body").
+			HTMLBody(`<p>body <img src="cid:logo"></p>`).
+			Inline("logo.png", "image/png", "logo", []byte{0x89, 'P', 'N', 'G'}).
+			Attach("notes.txt", "text/plain", []byte("attached notes"))
 	})
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+
+	// builder parameter with pluggable transport delivering to local smtp server
+	srv, err := NewSMTPServer("127.0.0.1:0")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	err = NewEmailSender(NewSMTPTransport(srv.Addr(), nil)).Send(func(b *EmailBuilder) {
+		b.From("foo@email.com").To("bar@email.com").Subject("notification").Body("done")
+	})
+	_ = srv.Close()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	for _, e := range srv.Emails() {
+		fmt.Printf("SMTP server received: %s -> %s (%d bytes)\n", e.From, strings.Join(e.To, ", "), len(e.Data))
+	}
 
 	// functional builder
-	p := NewPersonBuilder().
+	p, err := NewPersonBuilder().
 		Name("Tom").
 		YearOfBirth(1990).
 		Build()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 	fmt.Printf("Name: %s | YearOfBirth: %d \n", p.name, p.yearOfBirth)
 
+	// functional builder with validation errors
+	_, err = NewPersonBuilder().
+		YearOfBirth(2990).
+		Build()
+	fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+
 	// faceted builder
-	ep := NewEmployeeBuilder().
+	ep, err := NewEmployeeBuilder().
 		Address().
+		Country("US").
 		City("Los Angeles").
 		Street("501 N VIRGIL").
 		PostalCode("90004-2315").
@@ -59,11 +100,59 @@ func Builder() {
 		Department("Development").
 		Role("Software Engineer").
 		Build()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 	fmt.Printf(
-		"Address: %s %s %s | Job: %s %s \n",
-		ep.City, ep.Street, ep.PostalCode, ep.Department, ep.Role,
+		"Address: %s %s %s %s | Job: %s %s \n",
+		ep.Country, ep.City, ep.Street, ep.PostalCode, ep.Department, ep.Role,
 	)
 
+	// faceted builder with validation errors
+	_, err = NewEmployeeBuilder().
+		Address().
+		Country("GB").
+		PostalCode("90004-2315").
+		Job().
+		Role("Software Engineer").
+		Build()
+	fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+
+	// generated functional builder with validation hook
+	c, err := NewCustomerBuilder().
+		Name("Rick").
+		Email("rick@email.com").
+		Validate(func(c *Customer) error {
+			if !strings.Contains(c.email, "@") {
+				return errors.New("email: must contain @")
+			}
+			return nil
+		}).
+		Build()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("Customer: %s %s | Tier: %s %d \n", c.name, c.email, c.tier, c.credit)
+
+	// generated faceted builder
+	sh, err := NewShipmentBuilder().
+		TrackingID("TRK-42").
+		Sender().
+		SenderName("Morty").
+		Recipient().
+		RecipientName("Summer").
+		RecipientAddress("Smith residence").
+		Build()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf(
+		"Shipment: %s %.1fkg | From: %s %s | To: %s %s \n",
+		sh.trackingID, sh.weight, sh.senderName, sh.senderAddress, sh.recipientName, sh.recipientAddress,
+	)
 }
 
 // -- Builder with fluent interface
@@ -133,60 +222,6 @@ func (qb *QueryBuilder) appendArguments(q string, args []string) (string, error)
 	return result, nil
 }
 
-// -- Builder Parameter
-
-// email - represents email.
-type email struct {
-	from, to, subject, body string
-}
-
-// EmailBuilder - represents email builder.
-type EmailBuilder struct {
-	email email
-}
-
-// From - sets from field on email.
-func (mb *EmailBuilder) From(s string) *EmailBuilder {
-	mb.email.from = s
-	return mb
-}
-
-// To - sets to field on email.
-func (mb *EmailBuilder) To(s string) *EmailBuilder {
-	mb.email.to = s
-	return mb
-}
-
-// Subject - sets subject field on email.
-func (mb *EmailBuilder) Subject(s string) *EmailBuilder {
-	mb.email.subject = s
-	return mb
-}
-
-// Body - sets body field on email.
-func (mb *EmailBuilder) Body(s string) *EmailBuilder {
-	mb.email.body = s
-	return mb
-}
-
-// build - represents build type.
-type build func(*EmailBuilder)
-
-// sendEmail - sends email private method.
-func sendEmail(email *email) {
-	fmt.Printf(
-		"From: %s | To: %s | Subject: %s | Body: %s \n",
-		email.from, email.to, email.subject, email.body,
-	)
-}
-
-// SendEmailBuilderParameter - sends email public method.
-func SendEmailBuilderParameter(action build) {
-	mb := EmailBuilder{}
-	action(&mb)
-	sendEmail(&mb.email)
-}
-
 // -- Functional Builder
 
 // Person - represents person.
@@ -201,11 +236,21 @@ type personMod func(p *Person)
 // PersonBuilder - represents Person builder.
 type PersonBuilder struct {
 	actions []personMod
+	rules   ruleSet
 }
 
 // NewPersonBuilder - creates new instance of PersonBuilder.
 func NewPersonBuilder() *PersonBuilder {
-	return &PersonBuilder{}
+	pb := &PersonBuilder{}
+	pb.rules.add("name", Required(), Length(1, 100))
+	pb.rules.add("yearOfBirth", Required(), Range(1900, time.Now().Year()))
+	return pb
+}
+
+// Rule - appends validation rules of provided person field ("name", "yearOfBirth").
+func (pb *PersonBuilder) Rule(field string, rules ...Rule) *PersonBuilder {
+	pb.rules.add(field, rules...)
+	return pb
 }
 
 // Name - creates person name modifier and appends it to action list.
@@ -224,13 +269,20 @@ func (pb *PersonBuilder) YearOfBirth(i int) *PersonBuilder {
 	return pb
 }
 
-// Build - builds person object based on defined actions.
-func (pb *PersonBuilder) Build() *Person {
+// Build - builds person object based on defined actions, validates it against rules.
+func (pb *PersonBuilder) Build() (*Person, error) {
 	p := &Person{}
 	for _, action := range pb.actions {
 		action(p)
 	}
-	return p
+	err := pb.rules.validate(map[string]interface{}{
+		"name":        p.name,
+		"yearOfBirth": p.yearOfBirth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 // -- Faceted Builder
@@ -238,7 +290,7 @@ func (pb *PersonBuilder) Build() *Person {
 // Employee - represents employee.
 type Employee struct {
 	// address details
-	City, Street, PostalCode string
+	Country, City, Street, PostalCode string
 	// job details
 	Department, Role string
 }
@@ -250,12 +302,27 @@ func NewEmployee() *Employee {
 
 // EmployeeBuilder - represents Employee builder.
 type EmployeeBuilder struct {
-	e *Employee
+	e     *Employee
+	rules *ruleSet // shared between facets
 }
 
 // NewEmployeeBuilder - creates new instance of EmployeeBuilder.
 func NewEmployeeBuilder() *EmployeeBuilder {
-	return &EmployeeBuilder{NewEmployee()}
+	rules := &ruleSet{}
+	rules.add("country", Required(), Pattern(`^[A-Z]{2}$`, "ISO 3166-1 alpha-2 code"))
+	rules.add("city", Required())
+	rules.add("street", Required())
+	rules.add("postalCode", Required())
+	rules.add("department", Required())
+	rules.add("role", Required())
+	return &EmployeeBuilder{NewEmployee(), rules}
+}
+
+// Rule - appends validation rules of provided employee field
+// ("country", "city", "street", "postalCode", "department", "role").
+func (eb *EmployeeBuilder) Rule(field string, rules ...Rule) *EmployeeBuilder {
+	eb.rules.add(field, rules...)
+	return eb
 }
 
 // Address - grants access to methods required to construct Employee address details.
@@ -268,9 +335,25 @@ func (eb *EmployeeBuilder) Job() *EmployeeJobBuilder {
 	return NewEmployeeJobBuilder(eb)
 }
 
-// Build - returns instance built Employee.
-func (eb *EmployeeBuilder) Build() *Employee {
-	return eb.e
+// Build - returns instance built Employee, validates it against rules.
+func (eb *EmployeeBuilder) Build() (*Employee, error) {
+	rules := append(ruleSet{}, *eb.rules...)
+	// postal code format depends on country
+	if eb.e.Country != "" {
+		rules.add("postalCode", PostalCode(eb.e.Country))
+	}
+	err := rules.validate(map[string]interface{}{
+		"country":    eb.e.Country,
+		"city":       eb.e.City,
+		"street":     eb.e.Street,
+		"postalCode": eb.e.PostalCode,
+		"department": eb.e.Department,
+		"role":       eb.e.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eb.e, nil
 }
 
 // EmployeeAddressBuilder - represents Employee address builder.
@@ -283,6 +366,12 @@ func NewEmployeeAddressBuilder(eb *EmployeeBuilder) *EmployeeAddressBuilder {
 	return &EmployeeAddressBuilder{*eb}
 }
 
+// Country - sets Employee country field.
+func (ab *EmployeeAddressBuilder) Country(s string) *EmployeeAddressBuilder {
+	ab.e.Country = s
+	return ab
+}
+
 // City - sets Employee city field.
 func (ab *EmployeeAddressBuilder) City(s string) *EmployeeAddressBuilder {
 	ab.e.City = s
@@ -0,0 +1,98 @@
+// Code generated by buildergen; DO NOT EDIT.
+
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// -- Functional Builder
+
+// customerMod - represents Customer modifier.
+type customerMod func(v *Customer)
+
+// CustomerBuilder - represents Customer builder.
+type CustomerBuilder struct {
+	actions    []customerMod
+	validators []func(v *Customer) error
+}
+
+// NewCustomerBuilder - creates new instance of CustomerBuilder.
+func NewCustomerBuilder() *CustomerBuilder {
+	return &CustomerBuilder{}
+}
+
+// Name - creates Customer name modifier and appends it to action list.
+func (b *CustomerBuilder) Name(v string) *CustomerBuilder {
+	b.actions = append(b.actions, func(x *Customer) {
+		x.name = v
+	})
+	return b
+}
+
+// Email - creates Customer email modifier and appends it to action list.
+func (b *CustomerBuilder) Email(v string) *CustomerBuilder {
+	b.actions = append(b.actions, func(x *Customer) {
+		x.email = v
+	})
+	return b
+}
+
+// Tier - creates Customer tier modifier and appends it to action list.
+func (b *CustomerBuilder) Tier(v string) *CustomerBuilder {
+	b.actions = append(b.actions, func(x *Customer) {
+		x.tier = v
+	})
+	return b
+}
+
+// Credit - creates Customer credit modifier and appends it to action list.
+func (b *CustomerBuilder) Credit(v int) *CustomerBuilder {
+	b.actions = append(b.actions, func(x *Customer) {
+		x.credit = v
+	})
+	return b
+}
+
+// Validate - appends validation hook run on Build.
+func (b *CustomerBuilder) Validate(fn func(v *Customer) error) *CustomerBuilder {
+	b.validators = append(b.validators, fn)
+	return b
+}
+
+// Build - builds Customer object based on defined actions, validates it.
+func (b *CustomerBuilder) Build() (*Customer, error) {
+	v := &Customer{
+		tier:   "standard",
+		credit: 100,
+	}
+	for _, action := range b.actions {
+		action(v)
+	}
+	if err := validateCustomer(v, b.validators); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// validateCustomer - checks required Customer fields and runs validation hooks, accumulates every problem.
+func validateCustomer(v *Customer, validators []func(v *Customer) error) error {
+	var msgs []string
+	if reflect.ValueOf(v.name).IsZero() {
+		msgs = append(msgs, "name: is required")
+	}
+	if reflect.ValueOf(v.email).IsZero() {
+		msgs = append(msgs, "email: is required")
+	}
+	for _, fn := range validators {
+		if err := fn(v); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("invalid Customer: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
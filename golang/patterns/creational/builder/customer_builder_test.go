@@ -0,0 +1,50 @@
+// Code generated by buildergen; DO NOT EDIT.
+
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCustomerBuilderRequired(t *testing.T) {
+	_, err := NewCustomerBuilder().Build()
+	if err == nil {
+		t.Fatal("expected error for missing required fields")
+	}
+	for _, field := range []string{"name", "email"} {
+		if !strings.Contains(err.Error(), field+": is required") {
+			t.Errorf("expected %s to be reported, got %v", field, err)
+		}
+	}
+}
+
+func TestCustomerBuilderDefaults(t *testing.T) {
+	b := NewCustomerBuilder()
+	b.Name("sample name")
+	b.Email("sample email")
+	v, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.tier != "standard" {
+		t.Errorf("expected tier to be defaulted, got %v", v.tier)
+	}
+	if v.credit != 100 {
+		t.Errorf("expected credit to be defaulted, got %v", v.credit)
+	}
+	_ = v
+}
+
+func TestCustomerBuilderValidate(t *testing.T) {
+	b := NewCustomerBuilder()
+	b.Name("sample name")
+	b.Email("sample email")
+	_, err := b.Validate(func(v *Customer) error {
+		return errors.New("hook failed")
+	}).Build()
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Fatalf("expected validation hook error, got %v", err)
+	}
+}
@@ -0,0 +1,454 @@
+package builder
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -- Builder Parameter
+
+// email - represents email.
+type email struct {
+	from                 *mail.Address
+	to, cc, bcc, replyTo []*mail.Address
+	subject              string
+	date                 time.Time
+	headers              []emailHeader
+	body, html           string
+	attachments          []emailAttachment
+}
+
+// emailHeader - represents custom email header.
+type emailHeader struct {
+	key, value string
+}
+
+// emailAttachment - represents email attachment or inline resource.
+type emailAttachment struct {
+	filename, contentType, contentID string
+	data                             []byte
+	inline                           bool
+}
+
+// reservedHeaders - headers which are managed by EmailBuilder and can't be set directly.
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// EmailError - represents all problems found while building email.
+type EmailError struct {
+	Errors []error
+}
+
+// Error - lists every accumulated error (implements error).
+func (ee *EmailError) Error() string {
+	msgs := make([]string, 0, len(ee.Errors))
+	for _, err := range ee.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("invalid email: %s", strings.Join(msgs, "; "))
+}
+
+// EmailBuilder - represents email builder.
+type EmailBuilder struct {
+	email email
+	errs  []error
+}
+
+// parseAddresses - parses provided addresses, records an error for every invalid one.
+func (mb *EmailBuilder) parseAddresses(field string, ss []string) []*mail.Address {
+	addrs := make([]*mail.Address, 0, len(ss))
+	for _, s := range ss {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			mb.errs = append(mb.errs, fmt.Errorf("%s: invalid address %q: %w", field, s, err))
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// From - sets from field on email.
+func (mb *EmailBuilder) From(s string) *EmailBuilder {
+	if addrs := mb.parseAddresses("from", []string{s}); len(addrs) > 0 {
+		mb.email.from = addrs[0]
+	}
+	return mb
+}
+
+// To - appends recipients to to field on email.
+func (mb *EmailBuilder) To(ss ...string) *EmailBuilder {
+	mb.email.to = append(mb.email.to, mb.parseAddresses("to", ss)...)
+	return mb
+}
+
+// Cc - appends recipients to cc field on email.
+func (mb *EmailBuilder) Cc(ss ...string) *EmailBuilder {
+	mb.email.cc = append(mb.email.cc, mb.parseAddresses("cc", ss)...)
+	return mb
+}
+
+// Bcc - appends hidden recipients to email.
+func (mb *EmailBuilder) Bcc(ss ...string) *EmailBuilder {
+	mb.email.bcc = append(mb.email.bcc, mb.parseAddresses("bcc", ss)...)
+	return mb
+}
+
+// ReplyTo - appends addresses to reply-to field on email.
+func (mb *EmailBuilder) ReplyTo(ss ...string) *EmailBuilder {
+	mb.email.replyTo = append(mb.email.replyTo, mb.parseAddresses("reply-to", ss)...)
+	return mb
+}
+
+// Subject - sets subject field on email.
+func (mb *EmailBuilder) Subject(s string) *EmailBuilder {
+	if strings.ContainsAny(s, "\r\n") {
+		mb.errs = append(mb.errs, errors.New("subject: must not contain line breaks"))
+		return mb
+	}
+	mb.email.subject = s
+	return mb
+}
+
+// Date - sets date field on email, current time is used when omitted.
+func (mb *EmailBuilder) Date(t time.Time) *EmailBuilder {
+	mb.email.date = t
+	return mb
+}
+
+// Header - appends custom header to email.
+func (mb *EmailBuilder) Header(key, value string) *EmailBuilder {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+	switch {
+	case key == "" || strings.ContainsAny(key, ": \t\r\n"):
+		mb.errs = append(mb.errs, fmt.Errorf("header: invalid name %q", key))
+	case reservedHeaders[key]:
+		mb.errs = append(mb.errs, fmt.Errorf("header: %q is managed by builder", key))
+	case strings.ContainsAny(value, "\r\n"):
+		mb.errs = append(mb.errs, fmt.Errorf("header: %q value must not contain line breaks", key))
+	default:
+		mb.email.headers = append(mb.email.headers, emailHeader{key, value})
+	}
+	return mb
+}
+
+// Body - sets plain text body on email.
+func (mb *EmailBuilder) Body(s string) *EmailBuilder {
+	mb.email.body = s
+	return mb
+}
+
+// HTMLBody - sets html body on email, sent as an alternative to plain text body.
+func (mb *EmailBuilder) HTMLBody(s string) *EmailBuilder {
+	mb.email.html = s
+	return mb
+}
+
+// Attach - appends file attachment to email.
+func (mb *EmailBuilder) Attach(filename, contentType string, data []byte) *EmailBuilder {
+	return mb.addAttachment(emailAttachment{filename: filename, contentType: contentType, data: data})
+}
+
+// Inline - appends inline resource to email, html body may reference it as "cid:<contentID>".
+func (mb *EmailBuilder) Inline(filename, contentType, contentID string, data []byte) *EmailBuilder {
+	if contentID == "" || strings.ContainsAny(contentID, "<> \r\n") {
+		mb.errs = append(mb.errs, fmt.Errorf("inline: invalid content id %q", contentID))
+		return mb
+	}
+	return mb.addAttachment(emailAttachment{
+		filename: filename, contentType: contentType, contentID: contentID, data: data, inline: true,
+	})
+}
+
+// addAttachment - validates and appends attachment to email.
+func (mb *EmailBuilder) addAttachment(a emailAttachment) *EmailBuilder {
+	if a.filename == "" || strings.ContainsAny(a.filename, "\r\n") {
+		mb.errs = append(mb.errs, fmt.Errorf("attachment: invalid filename %q", a.filename))
+		return mb
+	}
+	if _, _, err := mime.ParseMediaType(a.contentType); err != nil {
+		mb.errs = append(mb.errs, fmt.Errorf("attachment: %q has invalid content type %q: %w", a.filename, a.contentType, err))
+		return mb
+	}
+	mb.email.attachments = append(mb.email.attachments, a)
+	return mb
+}
+
+// build - validates collected email fields and returns email ready to be sent.
+func (mb *EmailBuilder) build() (*email, error) {
+	errs := append([]error{}, mb.errs...)
+	if mb.email.from == nil {
+		errs = append(errs, errors.New("from: sender is required"))
+	}
+	if len(mb.email.to)+len(mb.email.cc)+len(mb.email.bcc) == 0 {
+		errs = append(errs, errors.New("to: at least one recipient is required"))
+	}
+	if len(errs) > 0 {
+		return nil, &EmailError{Errors: errs}
+	}
+
+	e := mb.email
+	if e.date.IsZero() {
+		e.date = time.Now()
+	}
+	return &e, nil
+}
+
+// build - represents build type.
+type build func(*EmailBuilder)
+
+// sendEmail - sends email private method.
+func sendEmail(t Transport, email *email) error {
+	buff := new(bytes.Buffer)
+	if err := email.render(buff); err != nil {
+		return err
+	}
+	return t.Send(email.from.Address, email.recipients(), buff.Bytes())
+}
+
+// SendEmailBuilderParameter - sends email public method.
+func SendEmailBuilderParameter(action build) error {
+	return NewEmailSender(NewWriterTransport(os.Stdout)).Send(action)
+}
+
+// EmailSender - represents email sender delivering built emails through transport.
+type EmailSender struct {
+	transport Transport
+}
+
+// NewEmailSender - creates new instance of EmailSender.
+func NewEmailSender(t Transport) *EmailSender {
+	return &EmailSender{transport: t}
+}
+
+// Send - builds email with provided action and delivers it through transport.
+func (s *EmailSender) Send(action build) error {
+	mb := EmailBuilder{}
+	action(&mb)
+	email, err := mb.build()
+	if err != nil {
+		return err
+	}
+	return sendEmail(s.transport, email)
+}
+
+// -- MIME rendering
+
+// recipients - returns envelope recipients including hidden ones.
+func (e *email) recipients() []string {
+	rcpts := make([]string, 0, len(e.to)+len(e.cc)+len(e.bcc))
+	for _, list := range [][]*mail.Address{e.to, e.cc, e.bcc} {
+		for _, addr := range list {
+			rcpts = append(rcpts, addr.Address)
+		}
+	}
+	return rcpts
+}
+
+// render - writes email as RFC 5322 message with MIME body into provided writer.
+func (e *email) render(w io.Writer) error {
+	root := e.rootPart()
+
+	hw := &headerWriter{w: w}
+	hw.address("From", e.from)
+	hw.addresses("Reply-To", e.replyTo)
+	hw.addresses("To", e.to)
+	hw.addresses("Cc", e.cc)
+	hw.write("Subject", mime.QEncoding.Encode("utf-8", e.subject))
+	hw.write("Date", e.date.Format(time.RFC1123Z))
+	hw.write("Message-ID", e.messageID())
+	hw.write("MIME-Version", "1.0")
+	for _, h := range e.headers {
+		hw.write(h.key, mime.QEncoding.Encode("utf-8", h.value))
+	}
+	for _, key := range sortedKeys(root.header) {
+		hw.write(key, root.header.Get(key))
+	}
+	hw.raw("\r\n")
+	if hw.err != nil {
+		return hw.err
+	}
+
+	return root.write(w)
+}
+
+// rootPart - arranges email content into nested mixed / related / alternative parts.
+func (e *email) rootPart() *mimePart {
+	var body *mimePart
+	switch {
+	case e.html != "" && e.body != "":
+		body = multipartPart("alternative", textPart("text/plain", e.body), textPart("text/html", e.html))
+	case e.html != "":
+		body = textPart("text/html", e.html)
+	default:
+		body = textPart("text/plain", e.body)
+	}
+
+	var inline, attached []*mimePart
+	for _, a := range e.attachments {
+		if a.inline {
+			inline = append(inline, attachmentPart(a))
+		} else {
+			attached = append(attached, attachmentPart(a))
+		}
+	}
+	if len(inline) > 0 {
+		body = multipartPart("related", append([]*mimePart{body}, inline...)...)
+	}
+	if len(attached) > 0 {
+		body = multipartPart("mixed", append([]*mimePart{body}, attached...)...)
+	}
+	return body
+}
+
+// messageID - generates unique message id within sender domain.
+func (e *email) messageID() string {
+	domain := "localhost"
+	if i := strings.LastIndex(e.from.Address, "@"); i >= 0 {
+		domain = e.from.Address[i+1:]
+	}
+	bs := make([]byte, 16)
+	_, _ = rand.Read(bs)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(bs), domain)
+}
+
+// mimePart - represents MIME entity, its headers and content writer.
+type mimePart struct {
+	header textproto.MIMEHeader
+	write  func(w io.Writer) error
+}
+
+// textPart - creates quoted-printable encoded text part.
+func textPart(contentType, s string) *mimePart {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"}))
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return &mimePart{header: h, write: func(w io.Writer) error {
+		qw := quotedprintable.NewWriter(w)
+		if _, err := io.WriteString(qw, s); err != nil {
+			return err
+		}
+		return qw.Close()
+	}}
+}
+
+// attachmentPart - creates base64 encoded attachment part.
+func attachmentPart(a emailAttachment) *mimePart {
+	disposition := "attachment"
+	h := textproto.MIMEHeader{}
+	if a.inline {
+		disposition = "inline"
+		h.Set("Content-ID", "<"+a.contentID+">")
+	}
+	mediaType, params, _ := mime.ParseMediaType(a.contentType)
+	params["name"] = a.filename
+	h.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	h.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.filename}))
+	h.Set("Content-Transfer-Encoding", "base64")
+	return &mimePart{header: h, write: func(w io.Writer) error {
+		encoded := base64.StdEncoding.EncodeToString(a.data)
+		for len(encoded) > 76 {
+			if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+				return err
+			}
+			encoded = encoded[76:]
+		}
+		_, err := io.WriteString(w, encoded+"\r\n")
+		return err
+	}}
+}
+
+// multipartPart - creates multipart entity of provided subtype wrapping provided parts.
+func multipartPart(subtype string, parts ...*mimePart) *mimePart {
+	boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}))
+	return &mimePart{header: h, write: func(w io.Writer) error {
+		mw := multipart.NewWriter(w)
+		if err := mw.SetBoundary(boundary); err != nil {
+			return err
+		}
+		for _, p := range parts {
+			pw, err := mw.CreatePart(p.header)
+			if err != nil {
+				return err
+			}
+			if err := p.write(pw); err != nil {
+				return err
+			}
+		}
+		return mw.Close()
+	}}
+}
+
+// headerWriter - writes message headers, remembers first occurred error.
+type headerWriter struct {
+	w   io.Writer
+	err error
+}
+
+// raw - writes raw string.
+func (hw *headerWriter) raw(s string) {
+	if hw.err != nil {
+		return
+	}
+	_, hw.err = io.WriteString(hw.w, s)
+}
+
+// write - writes single header line.
+func (hw *headerWriter) write(key, value string) {
+	hw.raw(key + ": " + value + "\r\n")
+}
+
+// address - writes single address header.
+func (hw *headerWriter) address(key string, addr *mail.Address) {
+	hw.write(key, addr.String())
+}
+
+// addresses - writes address list header, omitted when list is empty.
+func (hw *headerWriter) addresses(key string, addrs []*mail.Address) {
+	if len(addrs) == 0 {
+		return
+	}
+	ss := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		ss = append(ss, addr.String())
+	}
+	hw.write(key, strings.Join(ss, ",\r\n "))
+}
+
+// sortedKeys - returns header keys in stable order.
+func sortedKeys(h textproto.MIMEHeader) []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
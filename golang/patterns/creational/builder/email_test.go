@@ -0,0 +1,276 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// renderEmail - builds email with provided action, renders and parses it back.
+func renderEmail(t *testing.T, action build) *mail.Message {
+	t.Helper()
+	mb := EmailBuilder{}
+	action(&mb)
+	e, err := mb.build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buff := new(bytes.Buffer)
+	if err := e.render(buff); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := mail.ReadMessage(buff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return msg
+}
+
+// leaf - represents decoded content of single part.
+type leaf struct {
+	contentType, disposition string
+	data                     string
+}
+
+// walkParts - describes MIME structure, e.g. "mixed(text/plain,image/png)", and collects decoded leaf parts.
+func walkParts(t *testing.T, contentType, encoding, disposition string, r io.Reader, leaves *[]leaf) string {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		// multipart reader decodes quoted-printable parts itself and drops their encoding header
+		switch encoding {
+		case "base64":
+			r = base64.NewDecoder(base64.StdEncoding, r)
+		case "quoted-printable":
+			r = quotedprintable.NewReader(r)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", mediaType, err)
+		}
+		*leaves = append(*leaves, leaf{mediaType, disposition, string(data)})
+		return mediaType
+	}
+
+	var children []string
+	mr := multipart.NewReader(r, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", mediaType, err)
+		}
+		disposition, _, _ := mime.ParseMediaType(p.Header.Get("Content-Disposition"))
+		children = append(children, walkParts(
+			t, p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), disposition, p, leaves,
+		))
+	}
+	return strings.TrimPrefix(mediaType, "multipart/") + "(" + strings.Join(children, ",") + ")"
+}
+
+// addressStrings - formats parsed addresses as "Name <address>", with decoded names.
+func addressStrings(list []*mail.Address) []string {
+	ss := make([]string, 0, len(list))
+	for _, addr := range list {
+		s := "<" + addr.Address + ">"
+		if addr.Name != "" {
+			s = addr.Name + " " + s
+		}
+		ss = append(ss, s)
+	}
+	return ss
+}
+
+func TestEmailBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		action build
+		errs   []string
+	}{
+		{"missing sender and recipients", func(b *EmailBuilder) {}, []string{
+			"from: sender is required", "to: at least one recipient is required",
+		}},
+		{"invalid addresses", func(b *EmailBuilder) {
+			b.From("foo").To("bar@email.com", "bar@").Cc("<qux").Bcc("").ReplyTo("a b")
+		}, []string{
+			`from: invalid address "foo"`, `to: invalid address "bar@"`, `cc: invalid address "<qux"`,
+			`bcc: invalid address ""`, `reply-to: invalid address "a b"`, "from: sender is required",
+		}},
+		{"header injection", func(b *EmailBuilder) {
+			b.From("foo@email.com").To("bar@email.com").
+				Subject("hi\r\nBcc: victim@email.com").
+				Header("X-Note", "a\nb").
+				Header("Content-Type", "text/html").
+				Header("Bad Name", "value")
+		}, []string{
+			"subject: must not contain line breaks", `header: "X-Note" value must not contain line breaks`,
+			`header: "Content-Type" is managed by builder`, `header: invalid name "Bad Name"`,
+		}},
+		{"invalid attachments", func(b *EmailBuilder) {
+			b.From("foo@email.com").To("bar@email.com").
+				Attach("", "text/plain", nil).
+				Attach("notes.txt", "text/", nil).
+				Inline("logo.png", "image/png", "<logo>", nil)
+		}, []string{
+			`attachment: invalid filename ""`, `attachment: "notes.txt" has invalid content type "text/"`,
+			`inline: invalid content id "<logo>"`,
+		}},
+	}
+	for _, tt := range tests {
+		mb := EmailBuilder{}
+		tt.action(&mb)
+		_, err := mb.build()
+		var ee *EmailError
+		if !errors.As(err, &ee) {
+			t.Errorf("%s: expected EmailError, got %v", tt.name, err)
+			continue
+		}
+		if len(ee.Errors) != len(tt.errs) {
+			t.Errorf("%s: expected %d errors, got %d: %v", tt.name, len(tt.errs), len(ee.Errors), err)
+		}
+		for _, want := range tt.errs {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected %q to be reported, got %v", tt.name, want, err)
+			}
+		}
+	}
+}
+
+func TestEmailRenderHeaders(t *testing.T) {
+	date := time.Date(2021, 3, 14, 15, 9, 26, 0, time.UTC)
+	msg := renderEmail(t, func(b *EmailBuilder) {
+		b.From("Café Owner <foo@email.com>").
+			To("bar@email.com", "Baz <baz@email.com>").
+			Cc("qux@email.com").
+			Bcc("audit@email.com").
+			ReplyTo("noreply@email.com").
+			Header("x-priority", "3").
+			Header("X-Note", "naïve").
+			Subject("Sujet – café").
+			Date(date).
+			Body("body")
+	})
+
+	addresses := []struct {
+		key  string
+		want []string
+	}{
+		{"From", []string{"Café Owner <foo@email.com>"}},
+		{"To", []string{"<bar@email.com>", "Baz <baz@email.com>"}},
+		{"Cc", []string{"<qux@email.com>"}},
+		{"Reply-To", []string{"<noreply@email.com>"}},
+	}
+	for _, tt := range addresses {
+		list, err := msg.Header.AddressList(tt.key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.key, err)
+		}
+		if got := addressStrings(list); strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: expected %v, got %v", tt.key, tt.want, got)
+		}
+	}
+	if _, ok := msg.Header["Bcc"]; ok {
+		t.Errorf("expected no Bcc header, got %q", msg.Header.Get("Bcc"))
+	}
+
+	dec := new(mime.WordDecoder)
+	for key, want := range map[string]string{"Subject": "Sujet – café", "X-Note": "naïve", "X-Priority": "3"} {
+		raw := msg.Header.Get(key)
+		if strings.ContainsAny(raw, "–ïé") {
+			t.Errorf("%s: expected non-ASCII value to be encoded, got %q", key, raw)
+		}
+		got, err := dec.DecodeHeader(raw)
+		if err != nil || got != want {
+			t.Errorf("%s: expected %q, got %q, %v", key, want, got, err)
+		}
+	}
+	if got, err := msg.Header.Date(); err != nil || !got.Equal(date) {
+		t.Errorf("expected date %v, got %v, %v", date, got, err)
+	}
+	if got := msg.Header.Get("Message-Id"); !strings.HasPrefix(got, "<") || !strings.HasSuffix(got, "@email.com>") {
+		t.Errorf("expected message id within sender domain, got %q", got)
+	}
+	if got := msg.Header.Get("Mime-Version"); got != "1.0" {
+		t.Errorf("expected MIME version 1.0, got %q", got)
+	}
+}
+
+func TestEmailRenderMultipart(t *testing.T) {
+	logo := []byte{0x89, 'P', 'N', 'G', 0, 0xff}
+	notes := strings.Repeat("attached notes ", 10)
+
+	tests := []struct {
+		name      string
+		action    build
+		structure string
+		leaves    []leaf
+	}{
+		{"plain text", func(b *EmailBuilder) {
+			b.Body("body")
+		}, "text/plain", []leaf{
+			{"text/plain", "", "body"},
+		}},
+		{"html only", func(b *EmailBuilder) {
+			b.HTMLBody("<p>body</p>")
+		}, "text/html", []leaf{
+			{"text/html", "", "<p>body</p>"},
+		}},
+		{"alternative bodies", func(b *EmailBuilder) {
+			b.Body("body – café").HTMLBody("<p>body</p>")
+		}, "alternative(text/plain,text/html)", []leaf{
+			{"text/plain", "", "body – café"},
+			{"text/html", "", "<p>body</p>"},
+		}},
+		{"inline resource", func(b *EmailBuilder) {
+			b.HTMLBody(`<img src="cid:logo">`).Inline("logo.png", "image/png", "logo", logo)
+		}, "related(text/html,image/png)", []leaf{
+			{"text/html", "", `<img src="cid:logo">`},
+			{"image/png", "inline", string(logo)},
+		}},
+		{"everything", func(b *EmailBuilder) {
+			b.Body("body").
+				HTMLBody(`<img src="cid:logo">`).
+				Inline("logo.png", "image/png", "logo", logo).
+				Attach("notes.txt", "text/plain", []byte(notes))
+		}, "mixed(related(alternative(text/plain,text/html),image/png),text/plain)", []leaf{
+			{"text/plain", "", "body"},
+			{"text/html", "", `<img src="cid:logo">`},
+			{"image/png", "inline", string(logo)},
+			{"text/plain", "attachment", notes},
+		}},
+	}
+	for _, tt := range tests {
+		msg := renderEmail(t, func(b *EmailBuilder) {
+			b.From("foo@email.com").To("bar@email.com")
+			tt.action(b)
+		})
+		var leaves []leaf
+		got := walkParts(t, msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body, &leaves)
+		if got != tt.structure {
+			t.Errorf("%s: expected structure %s, got %s", tt.name, tt.structure, got)
+		}
+		if len(leaves) != len(tt.leaves) {
+			t.Errorf("%s: expected %d parts, got %d", tt.name, len(tt.leaves), len(leaves))
+			continue
+		}
+		for i, want := range tt.leaves {
+			if leaves[i] != want {
+				t.Errorf("%s: part %d: expected %+v, got %+v", tt.name, i, want, leaves[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package builder
+
+// -- Generated Builder
+
+// Builders of types below are generated by cmd/buildergen from `builder` struct tags.
+
+//go:generate go run github.com/Tamplier2911/gof-design-patterns/golang/cmd/buildergen -type Customer -tests
+//go:generate go run github.com/Tamplier2911/gof-design-patterns/golang/cmd/buildergen -type Shipment -tests
+
+// Customer - represents customer (functional builder).
+type Customer struct {
+	name   string `builder:"required"`
+	email  string `builder:"required"`
+	tier   string `builder:"default=standard"`
+	credit int    `builder:"default=100"`
+}
+
+// Shipment - represents shipment (faceted builder).
+type Shipment struct {
+	// shipment details
+	trackingID string  `builder:"required"`
+	weight     float64 `builder:"default=1.5"`
+	// sender details
+	senderName    string `builder:"required,facet=sender"`
+	senderAddress string `builder:"facet=sender,default=Warehouse 1"`
+	// recipient details
+	recipientName    string `builder:"required,facet=recipient"`
+	recipientAddress string `builder:"required,facet=recipient"`
+}
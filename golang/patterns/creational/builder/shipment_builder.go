@@ -0,0 +1,134 @@
+// Code generated by buildergen; DO NOT EDIT.
+
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// -- Faceted Builder
+
+// ShipmentBuilder - represents Shipment builder.
+type ShipmentBuilder struct {
+	v          *Shipment
+	validators *[]func(v *Shipment) error // shared between facets
+}
+
+// NewShipmentBuilder - creates new instance of ShipmentBuilder.
+func NewShipmentBuilder() *ShipmentBuilder {
+	return &ShipmentBuilder{
+		v: &Shipment{
+			weight:        1.5,
+			senderAddress: "Warehouse 1",
+		},
+		validators: &[]func(v *Shipment) error{},
+	}
+}
+
+// TrackingID - sets Shipment trackingID field.
+func (b *ShipmentBuilder) TrackingID(v string) *ShipmentBuilder {
+	b.v.trackingID = v
+	return b
+}
+
+// Weight - sets Shipment weight field.
+func (b *ShipmentBuilder) Weight(v float64) *ShipmentBuilder {
+	b.v.weight = v
+	return b
+}
+
+// Sender - grants access to methods required to construct Shipment sender details.
+func (b *ShipmentBuilder) Sender() *ShipmentSenderBuilder {
+	return NewShipmentSenderBuilder(b)
+}
+
+// Recipient - grants access to methods required to construct Shipment recipient details.
+func (b *ShipmentBuilder) Recipient() *ShipmentRecipientBuilder {
+	return NewShipmentRecipientBuilder(b)
+}
+
+// Validate - appends validation hook run on Build.
+func (b *ShipmentBuilder) Validate(fn func(v *Shipment) error) *ShipmentBuilder {
+	*b.validators = append(*b.validators, fn)
+	return b
+}
+
+// Build - returns instance of built Shipment, validates it.
+func (b *ShipmentBuilder) Build() (*Shipment, error) {
+	if err := validateShipment(b.v, *b.validators); err != nil {
+		return nil, err
+	}
+	return b.v, nil
+}
+
+// ShipmentSenderBuilder - represents Shipment sender builder.
+type ShipmentSenderBuilder struct {
+	ShipmentBuilder
+}
+
+// NewShipmentSenderBuilder - creates new instance of ShipmentSenderBuilder.
+func NewShipmentSenderBuilder(b *ShipmentBuilder) *ShipmentSenderBuilder {
+	return &ShipmentSenderBuilder{*b}
+}
+
+// SenderName - sets Shipment senderName field.
+func (b *ShipmentSenderBuilder) SenderName(v string) *ShipmentSenderBuilder {
+	b.v.senderName = v
+	return b
+}
+
+// SenderAddress - sets Shipment senderAddress field.
+func (b *ShipmentSenderBuilder) SenderAddress(v string) *ShipmentSenderBuilder {
+	b.v.senderAddress = v
+	return b
+}
+
+// ShipmentRecipientBuilder - represents Shipment recipient builder.
+type ShipmentRecipientBuilder struct {
+	ShipmentBuilder
+}
+
+// NewShipmentRecipientBuilder - creates new instance of ShipmentRecipientBuilder.
+func NewShipmentRecipientBuilder(b *ShipmentBuilder) *ShipmentRecipientBuilder {
+	return &ShipmentRecipientBuilder{*b}
+}
+
+// RecipientName - sets Shipment recipientName field.
+func (b *ShipmentRecipientBuilder) RecipientName(v string) *ShipmentRecipientBuilder {
+	b.v.recipientName = v
+	return b
+}
+
+// RecipientAddress - sets Shipment recipientAddress field.
+func (b *ShipmentRecipientBuilder) RecipientAddress(v string) *ShipmentRecipientBuilder {
+	b.v.recipientAddress = v
+	return b
+}
+
+// validateShipment - checks required Shipment fields and runs validation hooks, accumulates every problem.
+func validateShipment(v *Shipment, validators []func(v *Shipment) error) error {
+	var msgs []string
+	if reflect.ValueOf(v.trackingID).IsZero() {
+		msgs = append(msgs, "trackingID: is required")
+	}
+	if reflect.ValueOf(v.senderName).IsZero() {
+		msgs = append(msgs, "senderName: is required")
+	}
+	if reflect.ValueOf(v.recipientName).IsZero() {
+		msgs = append(msgs, "recipientName: is required")
+	}
+	if reflect.ValueOf(v.recipientAddress).IsZero() {
+		msgs = append(msgs, "recipientAddress: is required")
+	}
+	for _, fn := range validators {
+		if err := fn(v); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("invalid Shipment: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
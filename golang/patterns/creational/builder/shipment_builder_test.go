@@ -0,0 +1,54 @@
+// Code generated by buildergen; DO NOT EDIT.
+
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestShipmentBuilderRequired(t *testing.T) {
+	_, err := NewShipmentBuilder().Build()
+	if err == nil {
+		t.Fatal("expected error for missing required fields")
+	}
+	for _, field := range []string{"trackingID", "senderName", "recipientName", "recipientAddress"} {
+		if !strings.Contains(err.Error(), field+": is required") {
+			t.Errorf("expected %s to be reported, got %v", field, err)
+		}
+	}
+}
+
+func TestShipmentBuilderDefaults(t *testing.T) {
+	b := NewShipmentBuilder()
+	b.TrackingID("sample trackingID")
+	b.Sender().SenderName("sample senderName")
+	b.Recipient().RecipientName("sample recipientName")
+	b.Recipient().RecipientAddress("sample recipientAddress")
+	v, err := b.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.weight != 1.5 {
+		t.Errorf("expected weight to be defaulted, got %v", v.weight)
+	}
+	if v.senderAddress != "Warehouse 1" {
+		t.Errorf("expected senderAddress to be defaulted, got %v", v.senderAddress)
+	}
+	_ = v
+}
+
+func TestShipmentBuilderValidate(t *testing.T) {
+	b := NewShipmentBuilder()
+	b.TrackingID("sample trackingID")
+	b.Sender().SenderName("sample senderName")
+	b.Recipient().RecipientName("sample recipientName")
+	b.Recipient().RecipientAddress("sample recipientAddress")
+	_, err := b.Validate(func(v *Shipment) error {
+		return errors.New("hook failed")
+	}).Build()
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Fatalf("expected validation hook error, got %v", err)
+	}
+}
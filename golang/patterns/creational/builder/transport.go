@@ -0,0 +1,336 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// -- Transports
+
+// Transport - represents email delivery interface.
+type Transport interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// CapturedEmail - represents delivered email as it was seen by transport.
+type CapturedEmail struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// WriterTransport - represents transport printing emails into writer.
+type WriterTransport struct {
+	w io.Writer
+}
+
+// NewWriterTransport - creates new instance of WriterTransport.
+func NewWriterTransport(w io.Writer) *WriterTransport {
+	return &WriterTransport{w: w}
+}
+
+// Send - writes envelope and message into writer (implements Transport).
+func (wt *WriterTransport) Send(from string, to []string, msg []byte) error {
+	_, err := fmt.Fprintf(wt.w, "Envelope: %s -> %s\n%s\n", from, strings.Join(to, ", "), msg)
+	return err
+}
+
+// SMTPTransport - represents transport delivering emails to SMTP server.
+type SMTPTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPTransport - creates new instance of SMTPTransport, auth may be nil.
+func NewSMTPTransport(addr string, auth smtp.Auth) *SMTPTransport {
+	return &SMTPTransport{addr: addr, auth: auth}
+}
+
+// Send - delivers message to SMTP server (implements Transport).
+func (st *SMTPTransport) Send(from string, to []string, msg []byte) error {
+	if err := smtp.SendMail(st.addr, st.auth, from, to, msg); err != nil {
+		return fmt.Errorf("smtp transport: %w", err)
+	}
+	return nil
+}
+
+// MaildirTransport - represents transport dropping emails into maildir.
+type MaildirTransport struct {
+	dir string
+	seq uint64
+}
+
+// NewMaildirTransport - creates new instance of MaildirTransport, creates tmp, new and cur directories.
+func NewMaildirTransport(dir string) (*MaildirTransport, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("maildir transport: %w", err)
+		}
+	}
+	return &MaildirTransport{dir: dir}, nil
+}
+
+// Send - writes message into tmp and moves it into new once complete (implements Transport).
+func (mt *MaildirTransport) Send(from string, to []string, msg []byte) error {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	name := fmt.Sprintf(
+		"%d.M%dP%dQ%d.%s",
+		time.Now().Unix(), time.Now().Nanosecond()/1000, os.Getpid(), atomic.AddUint64(&mt.seq, 1),
+		strings.NewReplacer("/", "\\057", ":", "\\072").Replace(host),
+	)
+
+	tmp := filepath.Join(mt.dir, "tmp", name)
+	if err := ioutil.WriteFile(tmp, msg, 0o644); err != nil {
+		return fmt.Errorf("maildir transport: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(mt.dir, "new", name)); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("maildir transport: %w", err)
+	}
+	return nil
+}
+
+// CaptureTransport - represents in-memory transport, keeps every sent email.
+type CaptureTransport struct {
+	mu     sync.Mutex
+	emails []CapturedEmail
+}
+
+// NewCaptureTransport - creates new instance of CaptureTransport.
+func NewCaptureTransport() *CaptureTransport {
+	return &CaptureTransport{}
+}
+
+// Send - stores email in memory (implements Transport).
+func (ct *CaptureTransport) Send(from string, to []string, msg []byte) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.emails = append(ct.emails, CapturedEmail{
+		From: from,
+		To:   append([]string{}, to...),
+		Data: append([]byte{}, msg...),
+	})
+	return nil
+}
+
+// Emails - returns copy of captured emails.
+func (ct *CaptureTransport) Emails() []CapturedEmail {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	emails := make([]CapturedEmail, 0, len(ct.emails))
+	for _, e := range ct.emails {
+		emails = append(emails, CapturedEmail{
+			From: e.From,
+			To:   append([]string{}, e.To...),
+			Data: append([]byte{}, e.Data...),
+		})
+	}
+	return emails
+}
+
+// Reset - forgets captured emails.
+func (ct *CaptureTransport) Reset() {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.emails = nil
+}
+
+// -- Fake SMTP Server
+
+// SMTPIdleTimeout - time fake SMTP server waits for next command before dropping session.
+var SMTPIdleTimeout = time.Minute
+
+// SMTPServer - represents minimal local SMTP server recording received emails.
+type SMTPServer struct {
+	listener net.Listener
+	capture  *CaptureTransport
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// NewSMTPServer - creates new instance of SMTPServer listening on provided address, e.g. "127.0.0.1:0".
+func NewSMTPServer(addr string) (*SMTPServer, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp server: %w", err)
+	}
+	s := &SMTPServer{listener: l, capture: NewCaptureTransport(), conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr - returns address server is listening on.
+func (s *SMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Emails - returns copy of received emails.
+func (s *SMTPServer) Emails() []CapturedEmail {
+	return s.capture.Emails()
+}
+
+// Close - stops accepting connections, drops open sessions and waits for them to finish.
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// track - registers open connection, reports false when server is already closed.
+func (s *SMTPServer) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+// untrack - forgets finished connection.
+func (s *SMTPServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// serve - accepts connections until listener is closed.
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		if !s.track(conn) {
+			_ = conn.Close()
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrack(conn)
+			defer conn.Close()
+			_ = s.handle(conn)
+		}()
+	}
+}
+
+// handle - runs single SMTP session, idle session is dropped after SMTPIdleTimeout.
+func (s *SMTPServer) handle(conn net.Conn) error {
+	c := textproto.NewConn(conn)
+	var from string
+	var hasFrom bool // null reverse-path "<>" is valid sender, e.g. for bounces
+	var to []string
+	reply := func(code int, msg string) error {
+		return c.PrintfLine("%d %s", code, msg)
+	}
+
+	if err := reply(220, "localhost fake SMTP server ready"); err != nil {
+		return err
+	}
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(SMTPIdleTimeout)); err != nil {
+			return err
+		}
+		line, err := c.ReadLine()
+		if err != nil {
+			return err
+		}
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], line[i+1:]
+		}
+
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			err = reply(250, "localhost")
+		case "MAIL":
+			from, err = parsePath(arg, "FROM:")
+			if err != nil {
+				// failed MAIL starts no transaction, sender of earlier one is forgotten too
+				from, hasFrom, to = "", false, nil
+				err = reply(501, err.Error())
+				break
+			}
+			hasFrom, to = true, nil
+			err = reply(250, "OK")
+		case "RCPT":
+			var rcpt string
+			rcpt, err = parsePath(arg, "TO:")
+			if err != nil {
+				err = reply(501, err.Error())
+				break
+			}
+			to = append(to, rcpt)
+			err = reply(250, "OK")
+		case "DATA":
+			if !hasFrom || len(to) == 0 {
+				err = reply(503, "need MAIL and RCPT first")
+				break
+			}
+			if err = reply(354, "end data with <CR><LF>.<CR><LF>"); err != nil {
+				return err
+			}
+			var data []byte
+			if data, err = ioutil.ReadAll(c.DotReader()); err != nil {
+				return err
+			}
+			_ = s.capture.Send(from, to, data)
+			from, hasFrom, to = "", false, nil
+			err = reply(250, "OK: queued")
+		case "RSET":
+			from, hasFrom, to = "", false, nil
+			err = reply(250, "OK")
+		case "NOOP":
+			err = reply(250, "OK")
+		case "QUIT":
+			return reply(221, "bye")
+		default:
+			err = reply(502, "command not implemented")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parsePath - extracts address from "FROM:<addr> ..." or "TO:<addr> ..." argument.
+func parsePath(arg, prefix string) (string, error) {
+	if !strings.HasPrefix(strings.ToUpper(arg), prefix) {
+		return "", fmt.Errorf("expected %s<address>", prefix)
+	}
+	fields := strings.Fields(arg[len(prefix):])
+	if len(fields) == 0 {
+		return "", fmt.Errorf("expected %s<address>", prefix)
+	}
+	path := fields[0]
+	if !strings.HasPrefix(path, "<") || !strings.HasSuffix(path, ">") {
+		return "", errors.New("address must be enclosed in <>")
+	}
+	return path[1 : len(path)-1], nil
+}
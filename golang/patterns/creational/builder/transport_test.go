@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// notification - builds small email with one recipient of each kind.
+func notification(b *EmailBuilder) {
+	b.From("Foo <foo@email.com>").
+		To("bar@email.com").
+		Cc("qux@email.com").
+		Bcc("audit@email.com").
+		Subject("notification").
+		Body("done")
+}
+
+// startSMTPServer - starts local SMTP server closed when test ends.
+func startSMTPServer(t *testing.T) *SMTPServer {
+	t.Helper()
+	srv, err := NewSMTPServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+// checkDelivered - checks envelope and parsed headers of delivered email built by notification.
+func checkDelivered(t *testing.T, name, from string, to []string, data []byte) {
+	t.Helper()
+	if from != "foo@email.com" {
+		t.Errorf("%s: expected envelope sender foo@email.com, got %q", name, from)
+	}
+	if want := []string{"bar@email.com", "qux@email.com", "audit@email.com"}; !reflect.DeepEqual(to, want) {
+		t.Errorf("%s: expected envelope recipients %v, got %v", name, want, to)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", name, err)
+	}
+	if got := msg.Header.Get("Subject"); got != "notification" {
+		t.Errorf("%s: expected subject notification, got %q", name, got)
+	}
+	if got := msg.Header.Get("Bcc"); got != "" {
+		t.Errorf("%s: expected no Bcc header, got %q", name, got)
+	}
+}
+
+func TestSMTPTransportEndToEnd(t *testing.T) {
+	srv := startSMTPServer(t)
+	if err := NewEmailSender(NewSMTPTransport(srv.Addr(), nil)).Send(notification); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	emails := srv.Emails()
+	if len(emails) != 1 {
+		t.Fatalf("expected 1 received email, got %d", len(emails))
+	}
+	checkDelivered(t, "smtp", emails[0].From, emails[0].To, emails[0].Data)
+}
+
+func TestSMTPServerFailedMailResetsSender(t *testing.T) {
+	srv := startSMTPServer(t)
+	c, err := textproto.Dial("tcp", srv.Addr())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+
+	steps := []struct {
+		cmd  string
+		code int
+	}{
+		{"", 220},
+		{"HELO localhost", 250},
+		{"MAIL FROM:<foo@email.com>", 250},
+		{"MAIL FROM:foo@email.com", 501},
+		{"RCPT TO:<bar@email.com>", 250},
+		{"DATA", 503},
+		{"QUIT", 221},
+	}
+	for _, s := range steps {
+		if s.cmd != "" {
+			if err := c.PrintfLine("%s", s.cmd); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if _, _, err := c.ReadResponse(s.code); err != nil {
+			t.Errorf("%q: expected %d reply, got %v", s.cmd, s.code, err)
+		}
+	}
+	if emails := srv.Emails(); len(emails) != 0 {
+		t.Errorf("expected no received emails, got %d", len(emails))
+	}
+}
+
+func TestCaptureTransport(t *testing.T) {
+	ct := NewCaptureTransport()
+	sender := NewEmailSender(ct)
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(notification); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	emails := ct.Emails()
+	if len(emails) != 2 {
+		t.Fatalf("expected 2 captured emails, got %d", len(emails))
+	}
+	for _, e := range emails {
+		checkDelivered(t, "capture", e.From, e.To, e.Data)
+	}
+
+	// captured emails are copies, changing them does not change transport
+	emails[0].To[0] = "changed@email.com"
+	if got := ct.Emails()[0].To[0]; got != "bar@email.com" {
+		t.Errorf("expected captured recipient to stay bar@email.com, got %q", got)
+	}
+
+	ct.Reset()
+	if emails := ct.Emails(); len(emails) != 0 {
+		t.Errorf("expected no captured emails after reset, got %d", len(emails))
+	}
+}
+
+func TestMaildirTransport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "maildir")
+	mt, err := NewMaildirTransport(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sender := NewEmailSender(mt)
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(notification); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		sub   string
+		count int
+	}{{"tmp", 0}, {"new", 2}, {"cur", 0}} {
+		files, err := ioutil.ReadDir(filepath.Join(dir, tt.sub))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(files) != tt.count {
+			t.Errorf("%s: expected %d files, got %d", tt.sub, tt.count, len(files))
+		}
+		for _, f := range files {
+			data, err := ioutil.ReadFile(filepath.Join(dir, tt.sub, f.Name()))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// maildir keeps no envelope, recipients are read from headers
+			msg, err := mail.ReadMessage(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", f.Name(), err)
+			}
+			to, err := msg.Header.AddressList("To")
+			if err != nil || len(to) != 1 || to[0].Address != "bar@email.com" {
+				t.Errorf("%s: expected To bar@email.com, got %v, %v", f.Name(), to, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// -- Validation
+
+// Rule - represents declarative validation rule applied to a single field value.
+type Rule func(v interface{}) error
+
+// FieldError - represents validation problem of a single field.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error - describes field problem (implements error).
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Err)
+}
+
+// ValidationError - represents all field problems found while building object.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error - lists every field problem (implements error).
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Errors))
+	for _, fe := range ve.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(msgs, "; "))
+}
+
+// Required - fails when value is empty string or zero number.
+func Required() Rule {
+	return func(v interface{}) error {
+		switch t := v.(type) {
+		case string:
+			if strings.TrimSpace(t) == "" {
+				return errors.New("is required")
+			}
+		case int:
+			if t == 0 {
+				return errors.New("is required")
+			}
+		}
+		return nil
+	}
+}
+
+// Range - fails when number is outside of [min, max], empty values are skipped.
+func Range(min, max int) Rule {
+	return func(v interface{}) error {
+		if i, ok := v.(int); ok && i != 0 && (i < min || i > max) {
+			return fmt.Errorf("must be between %d and %d, got %d", min, max, i)
+		}
+		return nil
+	}
+}
+
+// Length - fails when string length is outside of [min, max], empty values are skipped.
+func Length(min, max int) Rule {
+	return func(v interface{}) error {
+		if s, ok := v.(string); ok && s != "" && (len([]rune(s)) < min || len([]rune(s)) > max) {
+			return fmt.Errorf("length must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// Pattern - fails when string doesn't match provided expression, empty values are skipped.
+func Pattern(expr, format string) Rule {
+	re := regexp.MustCompile(expr)
+	return func(v interface{}) error {
+		if s, ok := v.(string); ok && s != "" && !re.MatchString(s) {
+			return fmt.Errorf("must be %s, got %q", format, s)
+		}
+		return nil
+	}
+}
+
+// postalCodeFormats - postal code expressions by ISO 3166-1 alpha-2 country code.
+var postalCodeFormats = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"UA": regexp.MustCompile(`^\d{5}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+}
+
+// PostalCode - fails when string isn't valid postal code of provided country, empty values are skipped.
+func PostalCode(country string) Rule {
+	return func(v interface{}) error {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return nil
+		}
+		re, ok := postalCodeFormats[strings.ToUpper(country)]
+		if !ok {
+			return fmt.Errorf("unsupported country %q", country)
+		}
+		if !re.MatchString(strings.ToUpper(s)) {
+			return fmt.Errorf("%q is not valid postal code in %s", s, strings.ToUpper(country))
+		}
+		return nil
+	}
+}
+
+// fieldRules - represents rules of a single field.
+type fieldRules struct {
+	field string
+	rules []Rule
+}
+
+// ruleSet - represents ordered rules of every field.
+type ruleSet []fieldRules
+
+// add - appends rules of provided field.
+func (rs *ruleSet) add(field string, rules ...Rule) {
+	for i := range *rs {
+		if (*rs)[i].field == field {
+			(*rs)[i].rules = append((*rs)[i].rules, rules...)
+			return
+		}
+	}
+	*rs = append(*rs, fieldRules{field, rules})
+}
+
+// validate - checks field values against rules, accumulates every violation.
+func (rs ruleSet) validate(fields map[string]interface{}) error {
+	var errs []*FieldError
+	for _, fr := range rs {
+		v, ok := fields[fr.field]
+		if !ok {
+			errs = append(errs, &FieldError{Field: fr.field, Err: errors.New("unknown field")})
+			continue
+		}
+		for _, rule := range fr.rules {
+			if err := rule(v); err != nil {
+				errs = append(errs, &FieldError{Field: fr.field, Err: err})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
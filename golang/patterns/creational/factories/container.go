@@ -0,0 +1,401 @@
+package factories
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// -- Dependency Injection Container
+
+var (
+	ErrMissingProvider  = errors.New("missing provider")
+	ErrDependencyCycle  = errors.New("dependency cycle")
+	ErrInvalidProvider  = errors.New("invalid provider")
+	ErrDuplicateBinding = errors.New("duplicate binding")
+)
+
+// Lifetime - represents how long instance created by provider lives.
+type Lifetime int
+
+const (
+	LifetimeTransient Lifetime = iota // new instance on every resolution
+	LifetimeSingleton                 // one instance per container tree
+	LifetimeScoped                    // one instance per scope
+)
+
+// String - returns lifetime name.
+func (l Lifetime) String() string {
+	switch l {
+	case LifetimeSingleton:
+		return "singleton"
+	case LifetimeScoped:
+		return "scoped"
+	default:
+		return "transient"
+	}
+}
+
+// binding - identifies provided type, optionally by name.
+type binding struct {
+	typ  reflect.Type
+	name string
+}
+
+// String - returns readable binding.
+func (b binding) String() string {
+	if b.name == "" {
+		return b.typ.String()
+	}
+	return fmt.Sprintf("%s[%s]", b.typ, b.name)
+}
+
+// provider - represents registered constructor.
+type provider struct {
+	fn       reflect.Value
+	lifetime Lifetime
+	params   []binding
+	out      reflect.Type // constructor result type, may differ from bound interface
+}
+
+// ProvideOption - represents provider registration option.
+type ProvideOption func(p *provideConfig)
+
+// provideConfig - represents provider registration settings.
+type provideConfig struct {
+	lifetime Lifetime
+	name     string
+	as       reflect.Type
+	deps     []string
+	err      error // invalid option
+}
+
+// WithLifetime - sets lifetime of provided instances, transient by default.
+func WithLifetime(l Lifetime) ProvideOption {
+	return func(p *provideConfig) { p.lifetime = l }
+}
+
+// WithName - binds provider under a name, allows multiple implementations of one type.
+func WithName(name string) ProvideOption {
+	return func(p *provideConfig) { p.name = name }
+}
+
+// As - binds provider to interface, iface must be a nil pointer to interface, e.g. (*Introducer)(nil).
+func As(iface interface{}) ProvideOption {
+	return func(p *provideConfig) {
+		t := reflect.TypeOf(iface)
+		if t == nil || t.Kind() != reflect.Ptr {
+			p.err = fmt.Errorf("%w: As requires pointer to interface, got %T", ErrInvalidProvider, iface)
+			return
+		}
+		p.as = t.Elem()
+	}
+}
+
+// WithDependencyNames - names constructor parameters should be resolved by, in parameter order ("" for unnamed).
+func WithDependencyNames(names ...string) ProvideOption {
+	return func(p *provideConfig) { p.deps = names }
+}
+
+// instance - represents cached instance of a binding, built once by whoever locks it first.
+type instance struct {
+	mu    sync.Mutex
+	built bool
+	v     reflect.Value
+}
+
+// Container - represents dependency injection container resolving constructor graphs.
+//
+// Container lock guards registrations and caches only, constructors run without it,
+// so independent bindings resolve in parallel. Provider resolving from the container inside
+// its constructor takes *Container parameter: injected container continues the resolution,
+// so resolving binding which is still being built reports ErrDependencyCycle instead of waiting for itself.
+// Container captured any other way starts new resolution, which waits for bindings being built.
+type Container struct {
+	mu         *sync.Mutex // shared by container and its scopes
+	root       *Container
+	providers  map[binding]*provider
+	singletons map[binding]*instance
+	scoped     map[binding]*instance
+	chain      []binding // bindings being built by resolution this container was injected into
+}
+
+// NewContainer - creates new instance of Container.
+func NewContainer() *Container {
+	c := &Container{
+		mu:         &sync.Mutex{},
+		providers:  make(map[binding]*provider),
+		singletons: make(map[binding]*instance),
+		scoped:     make(map[binding]*instance),
+	}
+	c.root = c
+	return c
+}
+
+// Scope - creates child container sharing providers and singletons, but owning scoped instances.
+func (c *Container) Scope() *Container {
+	return &Container{
+		mu:         c.mu,
+		root:       c.root,
+		providers:  c.root.providers,
+		singletons: c.root.singletons,
+		scoped:     make(map[binding]*instance),
+		chain:      c.chain,
+	}
+}
+
+// errorType, containerType - reflected error interface and container injected into providers.
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	containerType = reflect.TypeOf((*Container)(nil))
+)
+
+// continued - returns container continuing resolution of provided chain.
+func (c *Container) continued(chain []binding) *Container {
+	cc := *c
+	cc.chain = chain
+	return &cc
+}
+
+// Provide - registers constructor, e.g. func(deps...) T or func(deps...) (T, error).
+func (c *Container) Provide(constructor interface{}, opts ...ProvideOption) error {
+	fn := reflect.ValueOf(constructor)
+	ft := fn.Type()
+	if ft.Kind() != reflect.Func {
+		return fmt.Errorf("%w: %s is not a function", ErrInvalidProvider, ft)
+	}
+	if ft.NumOut() == 0 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return fmt.Errorf("%w: %s must return T or (T, error)", ErrInvalidProvider, ft)
+	}
+
+	cfg := provideConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.err != nil {
+		return cfg.err
+	}
+	if len(cfg.deps) > ft.NumIn() {
+		return fmt.Errorf("%w: %s has %d parameters, %d names given", ErrInvalidProvider, ft, ft.NumIn(), len(cfg.deps))
+	}
+
+	p := &provider{fn: fn, lifetime: cfg.lifetime, out: ft.Out(0)}
+	for i := 0; i < ft.NumIn(); i++ {
+		b := binding{typ: ft.In(i)}
+		if i < len(cfg.deps) {
+			b.name = cfg.deps[i]
+		}
+		p.params = append(p.params, b)
+	}
+
+	b := binding{typ: p.out, name: cfg.name}
+	if cfg.as != nil {
+		if cfg.as.Kind() != reflect.Interface || !p.out.Implements(cfg.as) {
+			return fmt.Errorf("%w: %s doesn't implement %s", ErrInvalidProvider, p.out, cfg.as)
+		}
+		b.typ = cfg.as
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.providers[b]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateBinding, b)
+	}
+	c.providers[b] = p
+	return nil
+}
+
+// Resolve - resolves instance into target, target must be a pointer to requested type.
+func (c *Container) Resolve(target interface{}) error {
+	return c.ResolveNamed(target, "")
+}
+
+// ResolveNamed - resolves instance bound under provided name into target.
+func (c *Container) ResolveNamed(target interface{}, name string) error {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return fmt.Errorf("resolve target must be non-nil pointer, got %T", target)
+	}
+
+	v, err := c.resolve(binding{typ: tv.Elem().Type(), name: name})
+	if err != nil {
+		return err
+	}
+	tv.Elem().Set(v)
+	return nil
+}
+
+// Invoke - calls provided function with resolved arguments.
+func (c *Container) Invoke(fn interface{}) error {
+	return c.InvokeNamed(fn)
+}
+
+// InvokeNamed - calls provided function with arguments resolved by names, in parameter order ("" for unnamed).
+func (c *Container) InvokeNamed(fn interface{}, names ...string) error {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return fmt.Errorf("invoke target must be a function, got %T", fn)
+	}
+	if len(names) > fv.Type().NumIn() {
+		return fmt.Errorf("invoke target %T has %d parameters, %d names given", fn, fv.Type().NumIn(), len(names))
+	}
+
+	args := make([]reflect.Value, 0, fv.Type().NumIn())
+	for i := 0; i < fv.Type().NumIn(); i++ {
+		b := binding{typ: fv.Type().In(i)}
+		if i < len(names) {
+			b.name = names[i]
+		}
+		v, err := c.resolve(b)
+		if err != nil {
+			return err
+		}
+		args = append(args, v)
+	}
+
+	out := fv.Call(args)
+	if n := len(out); n > 0 && out[n-1].Type() == errorType && !out[n-1].IsNil() {
+		return out[n-1].Interface().(error)
+	}
+	return nil
+}
+
+// resolve - checks dependency graph of binding, then builds it, continuing chain of the container.
+func (c *Container) resolve(b binding) (reflect.Value, error) {
+	if b.typ == containerType && b.name == "" {
+		return reflect.ValueOf(c), nil
+	}
+	c.mu.Lock()
+	err := c.check(b, c.chain)
+	c.mu.Unlock()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return c.build(b, c.chain)
+}
+
+// check - reports missing providers and cycles, path holds bindings being checked, mutex must be held.
+func (c *Container) check(b binding, path []binding) error {
+	if err := cycle(b, path); err != nil {
+		return err
+	}
+	path = append(path[:len(path):len(path)], b)
+
+	p, ok := c.providers[b]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingProvider, formatPath(path))
+	}
+	for _, param := range p.params {
+		if param.typ == containerType && param.name == "" {
+			continue // injected, not provided
+		}
+		if err := c.check(param, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cycle - reports ErrDependencyCycle when binding is already on the path.
+func cycle(b binding, path []binding) error {
+	for i, prev := range path {
+		if prev == b {
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, formatPath(append(append([]binding(nil), path[i:]...), b)))
+		}
+	}
+	return nil
+}
+
+// build - constructs binding or returns its cached instance, chain holds bindings being built by this resolution.
+//
+// Cached instance is locked while being built. Binding already in the chain is a cycle reported before
+// locking, as the instance lock is held by this very resolution and waiting for it would never end.
+func (c *Container) build(b binding, chain []binding) (reflect.Value, error) {
+	if err := cycle(b, chain); err != nil {
+		return reflect.Value{}, err
+	}
+	chain = append(chain[:len(chain):len(chain)], b)
+
+	c.mu.Lock()
+	p, ok := c.providers[b]
+	if !ok {
+		c.mu.Unlock()
+		return reflect.Value{}, fmt.Errorf("%w: %s", ErrMissingProvider, b)
+	}
+	var cache map[binding]*instance
+	switch p.lifetime {
+	case LifetimeSingleton:
+		cache = c.singletons
+	case LifetimeScoped:
+		cache = c.scoped
+	}
+	var inst *instance
+	if cache != nil {
+		if inst = cache[b]; inst == nil {
+			inst = &instance{}
+			cache[b] = inst
+		}
+	}
+	c.mu.Unlock()
+
+	// singletons must not capture scoped dependencies, so they are built by root
+	owner := c
+	if p.lifetime == LifetimeSingleton {
+		owner = c.root
+	}
+	if inst == nil {
+		return owner.construct(b, p, chain)
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.built {
+		return inst.v, nil
+	}
+	v, err := owner.construct(b, p, chain)
+	if err != nil {
+		// failed construction is not cached, next resolution retries
+		return reflect.Value{}, err
+	}
+	inst.v, inst.built = v, true
+	return v, nil
+}
+
+// construct - builds dependencies and calls constructor, container parameter continues the chain.
+func (c *Container) construct(b binding, p *provider, chain []binding) (reflect.Value, error) {
+	args := make([]reflect.Value, 0, len(p.params))
+	for _, param := range p.params {
+		if param.typ == containerType && param.name == "" {
+			args = append(args, reflect.ValueOf(c.continued(chain)))
+			continue
+		}
+		v, err := c.build(param, chain)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args = append(args, v)
+	}
+
+	out := p.fn.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("constructing %s: %w", b, out[1].Interface().(error))
+	}
+	v := out[0]
+	if v.Type() != b.typ {
+		bound := reflect.New(b.typ).Elem()
+		bound.Set(v)
+		v = bound
+	}
+	return v, nil
+}
+
+// formatPath - formats resolution path.
+func formatPath(path []binding) string {
+	ss := make([]string, 0, len(path))
+	for _, b := range path {
+		ss = append(ss, b.String())
+	}
+	return strings.Join(ss, " -> ")
+}
@@ -0,0 +1,268 @@
+package factories
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counter - represents dependency with identity, so instances can be told apart.
+type counter struct {
+	n int
+}
+
+// service - represents dependent of counter.
+type service struct {
+	c *counter
+}
+
+// left, right - represent bindings resolving each other at construction.
+type (
+	left  struct{ r *right }
+	right struct{ l *left }
+)
+
+// newCounting - returns counter constructor numbering instances it creates.
+func newCounting() func() *counter {
+	var mu sync.Mutex
+	n := 0
+	return func() *counter {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return &counter{n: n}
+	}
+}
+
+// resolveWithin - resolves within limited time, so waiting resolution fails the test instead of hanging it.
+func resolveWithin(t *testing.T, c *Container, target interface{}) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- c.Resolve(target) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("resolution did not finish")
+		return nil
+	}
+}
+
+func TestContainerLifetimes(t *testing.T) {
+	tests := []struct {
+		lifetime    Lifetime
+		sameInScope bool // resolved twice within one scope
+		sameAcross  bool // resolved within two scopes
+	}{
+		{LifetimeTransient, false, false},
+		{LifetimeScoped, true, false},
+		{LifetimeSingleton, true, true},
+	}
+	for _, tt := range tests {
+		c := NewContainer()
+		if err := c.Provide(newCounting(), WithLifetime(tt.lifetime)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s1, s2 := c.Scope(), c.Scope()
+		var a, b, other *counter
+		for _, r := range []struct {
+			c      *Container
+			target **counter
+		}{{s1, &a}, {s1, &b}, {s2, &other}} {
+			if err := r.c.Resolve(r.target); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.lifetime, err)
+			}
+		}
+		if got := a == b; got != tt.sameInScope {
+			t.Errorf("%s: expected same instance within scope %v, got %v", tt.lifetime, tt.sameInScope, got)
+		}
+		if got := a == other; got != tt.sameAcross {
+			t.Errorf("%s: expected same instance across scopes %v, got %v", tt.lifetime, tt.sameAcross, got)
+		}
+	}
+}
+
+func TestContainerSingletonBuiltOnce(t *testing.T) {
+	c := NewContainer()
+	if err := c.Provide(newCounting(), WithLifetime(LifetimeSingleton)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	results := make([]*counter, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := c.Scope().Resolve(&results[i]); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, r := range results {
+		if r == nil || r.n != 1 {
+			t.Fatalf("expected every resolution to get first instance, got %+v", r)
+		}
+	}
+}
+
+func TestContainerNamedBindings(t *testing.T) {
+	c := NewContainer()
+	for _, p := range []struct {
+		n    int
+		name string
+	}{{1, ""}, {2, "second"}, {3, "third"}} {
+		n := p.n
+		if err := c.Provide(func() *counter { return &counter{n: n} }, WithName(p.name)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := c.Provide(func() *counter { return nil }, WithName("second")); !errors.Is(err, ErrDuplicateBinding) {
+		t.Errorf("expected ErrDuplicateBinding, got %v", err)
+	}
+	if err := c.Provide(func(c *counter) *service { return &service{c} }, WithDependencyNames("third")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var unnamed, second *counter
+	if err := c.Resolve(&unnamed); err != nil || unnamed.n != 1 {
+		t.Errorf("expected unnamed binding, got %+v, %v", unnamed, err)
+	}
+	if err := c.ResolveNamed(&second, "second"); err != nil || second.n != 2 {
+		t.Errorf("expected binding named second, got %+v, %v", second, err)
+	}
+	var s *service
+	if err := c.Resolve(&s); err != nil || s.c.n != 3 {
+		t.Errorf("expected dependency named third, got %+v, %v", s, err)
+	}
+	err := c.InvokeNamed(func(a, b *counter) {
+		if a.n != 2 || b.n != 1 {
+			t.Errorf("expected bindings second and unnamed, got %d and %d", a.n, b.n)
+		}
+	}, "second")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestContainerMissingProvider(t *testing.T) {
+	c := NewContainer()
+	if err := c.Provide(func(c *counter) *service { return &service{c} }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var s *service
+	if err := c.Resolve(&s); !errors.Is(err, ErrMissingProvider) {
+		t.Errorf("expected ErrMissingProvider, got %v", err)
+	}
+	var cnt *counter
+	if err := c.ResolveNamed(&cnt, "unknown"); !errors.Is(err, ErrMissingProvider) {
+		t.Errorf("expected ErrMissingProvider, got %v", err)
+	}
+	if err := c.Invoke(func(s *service) {}); !errors.Is(err, ErrMissingProvider) {
+		t.Errorf("expected ErrMissingProvider, got %v", err)
+	}
+}
+
+func TestContainerCycles(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []interface{}
+		lifetime  Lifetime
+	}{
+		{"constructors depending on each other", []interface{}{
+			func(r *right) *left { return &left{r} },
+			func(l *left) *right { return &right{l} },
+		}, LifetimeTransient},
+		{"singleton resolving itself", []interface{}{
+			func(c *Container) (*counter, error) {
+				var self *counter
+				return &counter{}, c.Resolve(&self)
+			},
+		}, LifetimeSingleton},
+		{"scoped resolving itself", []interface{}{
+			func(c *Container) (*counter, error) {
+				var self *counter
+				return &counter{}, c.Resolve(&self)
+			},
+		}, LifetimeScoped},
+		{"singletons resolving each other", []interface{}{
+			func(c *Container) (*left, error) {
+				var r *right
+				return &left{r}, c.Resolve(&r)
+			},
+			func(c *Container) (*right, error) {
+				var l *left
+				return &right{l}, c.Resolve(&l)
+			},
+		}, LifetimeSingleton},
+		{"transients resolving each other", []interface{}{
+			func(c *Container) (*left, error) {
+				var r *right
+				return &left{r}, c.Resolve(&r)
+			},
+			func(c *Container) (*right, error) {
+				var l *left
+				return &right{l}, c.Scope().Resolve(&l)
+			},
+		}, LifetimeTransient},
+	}
+	for _, tt := range tests {
+		c := NewContainer()
+		for _, p := range tt.providers {
+			if err := c.Provide(p, WithLifetime(tt.lifetime)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+		}
+		var target interface{} = new(*left)
+		if len(tt.providers) == 1 {
+			target = new(*counter)
+		}
+		if err := resolveWithin(t, c, target); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("%s: expected ErrDependencyCycle, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestContainerProviderResolvesDependency(t *testing.T) {
+	c := NewContainer()
+	if err := c.Provide(newCounting(), WithLifetime(LifetimeSingleton)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := c.Provide(func(c *Container) (*service, error) {
+		s := &service{}
+		return s, c.Resolve(&s.c)
+	}, WithLifetime(LifetimeSingleton))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var s *service
+	if err := resolveWithin(t, c, &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.c == nil || s.c.n != 1 {
+		t.Errorf("expected singleton counter, got %+v", s.c)
+	}
+}
+
+func TestContainerInvalidProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor interface{}
+		opts        []ProvideOption
+	}{
+		{"not a function", 42, nil},
+		{"no result", func() {}, nil},
+		{"second result not error", func() (*counter, int) { return nil, 0 }, nil},
+		{"too many dependency names", func() *counter { return nil }, []ProvideOption{WithDependencyNames("a")}},
+		{"as nil", func() *counter { return nil }, []ProvideOption{As(nil)}},
+		{"as non-pointer", func() *counter { return nil }, []ProvideOption{As(Introducer(nil))}},
+		{"as non-interface", func() *counter { return nil }, []ProvideOption{As((*counter)(nil))}},
+		{"as unimplemented interface", func() *counter { return nil }, []ProvideOption{As((*Introducer)(nil))}},
+	}
+	for _, tt := range tests {
+		if err := NewContainer().Provide(tt.constructor, tt.opts...); !errors.Is(err, ErrInvalidProvider) {
+			t.Errorf("%s: expected ErrInvalidProvider, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package factories
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -- Employee Directory
+
+var (
+	ErrUnknownEmployee = errors.New("unknown employee")
+	ErrInvalidManager  = errors.New("invalid manager")
+)
+
+// IDGenerator - represents unique employee id generator.
+type IDGenerator interface {
+	NextID() string
+}
+
+// SequentialIDs - represents generator of sequential ids, e.g. E-0001.
+type SequentialIDs struct {
+	mu     sync.Mutex
+	prefix string
+	next   int
+}
+
+// NewSequentialIDs - creates new instance of SequentialIDs.
+func NewSequentialIDs(prefix string) *SequentialIDs {
+	return &SequentialIDs{prefix: prefix, next: 1}
+}
+
+// NextID - returns next id in sequence (implements IDGenerator).
+func (s *SequentialIDs) NextID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("%s%04d", s.prefix, s.next)
+	s.next++
+	return id
+}
+
+// UUIDs - represents generator of random (version 4) UUIDs.
+type UUIDs struct{}
+
+// NewUUIDs - creates new instance of UUIDs.
+func NewUUIDs() *UUIDs {
+	return &UUIDs{}
+}
+
+// NextID - returns random UUID (implements IDGenerator).
+func (u *UUIDs) NextID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("reading random bytes: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Directory - represents registry of hired employees.
+type Directory struct {
+	mu        sync.RWMutex
+	ids       IDGenerator
+	now       func() time.Time
+	employees map[string]*Employee
+	order     []string // hire order
+}
+
+// NewDirectory - creates new instance of Directory.
+func NewDirectory(ids IDGenerator) *Directory {
+	return &Directory{ids: ids, now: time.Now, employees: make(map[string]*Employee)}
+}
+
+// DefaultDirectory - directory every factory hires employees into unless told otherwise.
+var DefaultDirectory = NewDirectory(NewSequentialIDs("E-"))
+
+// Hire - stores copy of employee with assigned id and hire date, returns another copy of it.
+// Directory owns stored employees, changing returned copy doesn't affect directory.
+func (d *Directory) Hire(e *Employee) *Employee {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stored := *e
+	stored.ID = d.ids.NextID()
+	stored.HiredAt = d.now()
+	d.employees[stored.ID] = &stored
+	d.order = append(d.order, stored.ID)
+	hired := stored
+	return &hired
+}
+
+// Get - retrieves copy of employee by id.
+func (d *Directory) Get(id string) (*Employee, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	e, ok := d.employees[id]
+	if !ok {
+		return nil, fmt.Errorf("employee %q: %w", id, ErrUnknownEmployee)
+	}
+	found := *e
+	return &found, nil
+}
+
+// SetManager - links employee to manager, rejects links creating management cycles.
+func (d *Directory) SetManager(id, managerID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.employees[id]
+	if !ok {
+		return fmt.Errorf("employee %q: %w", id, ErrUnknownEmployee)
+	}
+	if managerID == "" {
+		e.ManagerID = ""
+		return nil
+	}
+	if _, ok := d.employees[managerID]; !ok {
+		return fmt.Errorf("manager %q: %w", managerID, ErrUnknownEmployee)
+	}
+	for cur := managerID; cur != ""; cur = d.employees[cur].ManagerID {
+		if cur == id {
+			return fmt.Errorf("%w: %q would manage itself", ErrInvalidManager, id)
+		}
+	}
+	e.ManagerID = managerID
+	return nil
+}
+
+// filter - returns copies of employees matching predicate in hire order.
+func (d *Directory) filter(match func(e *Employee) bool) []*Employee {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	es := make([]*Employee, 0)
+	for _, id := range d.order {
+		if e := d.employees[id]; match(e) {
+			found := *e
+			es = append(es, &found)
+		}
+	}
+	return es
+}
+
+// All - returns every employee in hire order.
+func (d *Directory) All() []*Employee {
+	return d.filter(func(e *Employee) bool { return true })
+}
+
+// ByDepartment - returns employees of provided department.
+func (d *Directory) ByDepartment(dep Department) []*Employee {
+	return d.filter(func(e *Employee) bool { return e.Department == dep })
+}
+
+// ByRole - returns employees of provided role.
+func (d *Directory) ByRole(r Role) []*Employee {
+	return d.filter(func(e *Employee) bool { return e.Role == r })
+}
+
+// Reports - returns direct reports of provided manager.
+func (d *Directory) Reports(managerID string) []*Employee {
+	return d.filter(func(e *Employee) bool { return e.ManagerID == managerID })
+}
+
+// Walk - traverses org chart depth first starting from employees without manager.
+// Chart is taken as it was when walk started, fn may change directory meanwhile.
+func (d *Directory) Walk(fn func(e *Employee, depth int)) {
+	reports := make(map[string][]*Employee)
+	for _, e := range d.All() {
+		reports[e.ManagerID] = append(reports[e.ManagerID], e)
+	}
+
+	var walk func(managerID string, depth int)
+	walk = func(managerID string, depth int) {
+		for _, e := range reports[managerID] {
+			fn(e, depth)
+			walk(e.ID, depth+1)
+		}
+	}
+	walk("", 0)
+}
+
+// OrgChart - renders org chart as indented tree.
+func (d *Directory) OrgChart() string {
+	var sb strings.Builder
+	d.Walk(func(e *Employee, depth int) {
+		fmt.Fprintf(&sb, "%s- %s (%s, %s)\n", strings.Repeat("  ", depth), e.Name, e.Role, e.ID)
+	})
+	return sb.String()
+}
+
+// ExportJSON - writes every employee as JSON array.
+func (d *Directory) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.All())
+}
+
+// ExportCSV - writes every employee as CSV with header row.
+func (d *Directory) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "name", "department", "role", "level", "team", "manager_id", "hired_at"})
+	for _, e := range d.All() {
+		_ = cw.Write([]string{
+			e.ID, e.Name, string(e.Department), string(e.Role), e.Level, e.Team, e.ManagerID,
+			e.HiredAt.Format(time.RFC3339),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,61 @@
+package factories
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// newOrgDirectory - creates directory with small org chart, employees are hired in provided order.
+func newOrgDirectory(t *testing.T) *Directory {
+	t.Helper()
+	d := NewDirectory(NewSequentialIDs("E-"))
+	ids := make(map[string]string)
+	for _, name := range []string{"ceo", "cto", "cfo", "dev1", "dev2", "accountant"} {
+		ids[name] = d.Hire(&Employee{Name: name}).ID
+	}
+	for name, manager := range map[string]string{
+		"cto": "ceo", "cfo": "ceo", "dev1": "cto", "dev2": "cto", "accountant": "cfo",
+	} {
+		if err := d.SetManager(ids[name], ids[manager]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return d
+}
+
+func TestDirectoryWalk(t *testing.T) {
+	var got []string
+	newOrgDirectory(t).Walk(func(e *Employee, depth int) {
+		got = append(got, fmt.Sprintf("%d %s", depth, e.Name))
+	})
+	want := []string{"0 ceo", "1 cto", "2 dev1", "2 dev2", "1 cfo", "2 accountant"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDirectoryWalkChangingDirectory(t *testing.T) {
+	d := newOrgDirectory(t)
+	visited := 0
+	d.Walk(func(e *Employee, depth int) {
+		visited++
+		if visited > 100 {
+			return // walk keeps finding hires made during it, stop adding more
+		}
+		// every visited employee gets new report, dev1 (E-0004) loses its manager
+		hired := d.Hire(&Employee{Name: "intern"})
+		if err := d.SetManager(hired.ID, e.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := d.SetManager("E-0004", ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if visited != 6 {
+		t.Errorf("expected walk to visit 6 employees it started with, got %d", visited)
+	}
+	if got := len(d.All()); got != 12 {
+		t.Errorf("expected 12 employees after walk, got %d", got)
+	}
+}
@@ -1,6 +1,10 @@
 package factories
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"time"
+)
 
 // Factories: are components responsible solely for the wholesale (not piecewise) creation of objects.
 //
@@ -32,10 +36,86 @@ func Factories() {
 	e2 := employeeEngineeringFactory("Tom")
 
 	// prototype factory
-	e3 := NewEmployeeFactoryP(RoleSoftwareEngineer)
-	e3.Name = "Jane"
+	e3, err := NewEmployeeFactoryP(RoleSoftwareEngineer, "Jane")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 
 	fmt.Printf("%+v\n%+v\n%+v\n", e1, e2, e3)
+
+	// registry factory extended at runtime
+	const _path = "./patterns/creational/factories/files/roles.json"
+	reg := NewEmployeeRegistry()
+	if err := reg.LoadFile(_path); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	e4, err := reg.Create("product designer", "Dieter")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("%+v\n", e4)
+
+	// employee directory
+	if err := DefaultDirectory.SetManager(e2.ID, e3.ID); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	if err := DefaultDirectory.SetManager(e1.ID, e3.ID); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("engineers: %d\n", len(DefaultDirectory.ByDepartment(DepartmentEngineering)))
+	fmt.Print(DefaultDirectory.OrgChart())
+	if err := DefaultDirectory.ExportCSV(os.Stdout); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+
+	// unknown roles are reported instead of panicking
+	if _, err := NewEmployeeFactoryP("astronaut", "Neil"); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
+	// dependency injection container
+	c := NewContainer()
+	providers := []struct {
+		constructor interface{}
+		opts        []ProvideOption
+	}{
+		{func() Department { return DepartmentEngineering }, nil},
+		{func() Role { return RoleSoftwareEngineer }, nil},
+		{NewEmployeeFactoryS, []ProvideOption{WithLifetime(LifetimeSingleton)}},
+		{func() Introducer { return NewIntroducer("Charles", "de Gaulle") }, []ProvideOption{WithName("general")}},
+		{func() Introducer { return NewIntroducer("Jeanne", "d'Arc") }, []ProvideOption{WithName("saint")}},
+	}
+	for _, p := range providers {
+		if err := c.Provide(p.constructor, p.opts...); err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+	}
+	err = c.Scope().Invoke(func(ef *EmployeeFactory) {
+		fmt.Printf("%+v\n", ef.Create("Linus"))
+	})
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	var saint Introducer
+	if err := c.ResolveNamed(&saint, "saint"); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	saint.Introduce()
+
+	// missing providers are reported with resolution path
+	var er *EmployeeRegistry
+	if err := c.Resolve(&er); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
 }
 
 // -- Factory Function / Constructor
@@ -71,9 +151,15 @@ func NewIntroducer(firstName string, lastName string) Introducer {
 
 // Employee - represents employee.
 type Employee struct {
-	Name       string
-	Department Department
-	Role       Role
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Department Department `json:"department"`
+	Role       Role       `json:"role"`
+	Level      string     `json:"level,omitempty"`
+	Team       string     `json:"team,omitempty"`
+	Salary     SalaryBand `json:"salary"`
+	ManagerID  string     `json:"managerId,omitempty"`
+	HiredAt    time.Time  `json:"hiredAt"`
 }
 
 // Department - represent employee department.
@@ -97,6 +183,7 @@ const (
 type EmployeeFactory struct {
 	Department Department
 	Role       Role
+	Directory  *Directory // DefaultDirectory when nil
 }
 
 // NewEmployeeFactoryS - s instance of new Employee structural factory.
@@ -104,30 +191,44 @@ func NewEmployeeFactoryS(department Department, role Role) *EmployeeFactory {
 	return &EmployeeFactory{Department: department, Role: role}
 }
 
-// Create - creates new instance of Employee.
+// Create - creates new instance of Employee and hires it into directory.
 func (e EmployeeFactory) Create(name string) *Employee {
-	return &Employee{Name: name, Department: e.Department, Role: e.Role}
+	dir := e.Directory
+	if dir == nil {
+		dir = DefaultDirectory
+	}
+	return dir.Hire(&Employee{Name: name, Department: e.Department, Role: e.Role})
 }
 
 // functional approach
 
+// FactoryOption - represents functional factory option.
+type FactoryOption func(c *factoryConfig)
+
+// factoryConfig - represents functional factory settings.
+type factoryConfig struct {
+	directory *Directory
+}
+
+// WithDirectory - sets directory created employees are hired into, DefaultDirectory by default.
+func WithDirectory(d *Directory) FactoryOption {
+	return func(c *factoryConfig) { c.directory = d }
+}
+
 // NewEmployeeFactoryF - creates instance of new Employee functional factory.
-func NewEmployeeFactoryF(d Department, r Role) func(name string) *Employee {
+func NewEmployeeFactoryF(d Department, r Role, opts ...FactoryOption) func(name string) *Employee {
+	cfg := factoryConfig{directory: DefaultDirectory}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	return func(name string) *Employee {
-		return &Employee{Name: name, Department: d, Role: r}
+		return cfg.directory.Hire(&Employee{Name: name, Department: d, Role: r})
 	}
 }
 
 // -- Prototype Factory
 
-// NewEmployeeFactoryP - creates instance of new Employee prototype factory.
-func NewEmployeeFactoryP(r Role) *Employee {
-	switch r {
-	case RoleMarketingAnalyst:
-		return &Employee{Name: "", Department: DepartmentMarketing, Role: r}
-	case RoleSoftwareEngineer:
-		return &Employee{Name: "", Department: DepartmentEngineering, Role: r}
-	default:
-		panic("invalid role")
-	}
+// NewEmployeeFactoryP - creates instance of new named Employee from role prototype of default registry.
+func NewEmployeeFactoryP(r Role, name string) (*Employee, error) {
+	return DefaultEmployeeRegistry.Create(r, name)
 }
@@ -0,0 +1,243 @@
+package factories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// -- Factory Registry
+
+var (
+	ErrUnknownDepartment = errors.New("unknown department")
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrAlreadyRegistered = errors.New("already registered")
+)
+
+// SalaryBand - represents salary range of a role.
+type SalaryBand struct {
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	Currency string `json:"currency"`
+}
+
+// RolePrototype - represents defaults every Employee of a role is created with.
+type RolePrototype struct {
+	Role       Role       `json:"role"`
+	Department Department `json:"department"`
+	Level      string     `json:"level"`
+	Team       string     `json:"team"`
+	Salary     SalaryBand `json:"salary"`
+}
+
+// EmployeeRegistry - represents runtime extensible registry of departments and role prototypes.
+type EmployeeRegistry struct {
+	mu          sync.RWMutex
+	departments map[Department]bool
+	roles       map[Role]RolePrototype
+	directory   *Directory
+}
+
+// NewEmployeeRegistry - creates new instance of empty EmployeeRegistry.
+func NewEmployeeRegistry() *EmployeeRegistry {
+	return &EmployeeRegistry{
+		departments: make(map[Department]bool),
+		roles:       make(map[Role]RolePrototype),
+		directory:   DefaultDirectory,
+	}
+}
+
+// SetDirectory - sets directory created employees are hired into.
+func (er *EmployeeRegistry) SetDirectory(d *Directory) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	er.directory = d
+}
+
+// RegisterDepartment - registers new department.
+func (er *EmployeeRegistry) RegisterDepartment(d Department) error {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	if err := checkDepartment(d, er.departments); err != nil {
+		return err
+	}
+	er.departments[d] = true
+	return nil
+}
+
+// RegisterRole - registers new role prototype, its department must be registered first.
+func (er *EmployeeRegistry) RegisterRole(p RolePrototype) error {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	if err := checkRole(p, er.departments, er.roles); err != nil {
+		return err
+	}
+	er.roles[p.Role] = p
+	return nil
+}
+
+// checkDepartment - validates department against already known departments.
+func checkDepartment(d Department, departments map[Department]bool) error {
+	if d == "" {
+		return errors.New("department name is required")
+	}
+	if departments[d] {
+		return fmt.Errorf("department %q: %w", d, ErrAlreadyRegistered)
+	}
+	return nil
+}
+
+// checkRole - validates role prototype against already known departments and roles.
+func checkRole(p RolePrototype, departments map[Department]bool, roles map[Role]RolePrototype) error {
+	if p.Role == "" {
+		return errors.New("role name is required")
+	}
+	if p.Salary.Min < 0 || p.Salary.Min > p.Salary.Max {
+		return fmt.Errorf("role %q: invalid salary band %d-%d", p.Role, p.Salary.Min, p.Salary.Max)
+	}
+	if !departments[p.Department] {
+		return fmt.Errorf("role %q: department %q: %w", p.Role, p.Department, ErrUnknownDepartment)
+	}
+	if _, ok := roles[p.Role]; ok {
+		return fmt.Errorf("role %q: %w", p.Role, ErrAlreadyRegistered)
+	}
+	return nil
+}
+
+// Lookup - retrieves role prototype.
+func (er *EmployeeRegistry) Lookup(r Role) (RolePrototype, error) {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	p, ok := er.roles[r]
+	if !ok {
+		return RolePrototype{}, fmt.Errorf("role %q: %w", r, ErrUnknownRole)
+	}
+	return p, nil
+}
+
+// Create - creates new instance of Employee from role prototype and hires it into directory.
+func (er *EmployeeRegistry) Create(r Role, name string) (*Employee, error) {
+	p, err := er.Lookup(r)
+	if err != nil {
+		return nil, err
+	}
+	er.mu.RLock()
+	dir := er.directory
+	er.mu.RUnlock()
+	return dir.Hire(&Employee{
+		Name:       name,
+		Department: p.Department,
+		Role:       p.Role,
+		Level:      p.Level,
+		Team:       p.Team,
+		Salary:     p.Salary,
+	}), nil
+}
+
+// Departments - lists registered departments.
+func (er *EmployeeRegistry) Departments() []Department {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	ds := make([]Department, 0, len(er.departments))
+	for d := range er.departments {
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return ds
+}
+
+// Roles - lists registered roles.
+func (er *EmployeeRegistry) Roles() []Role {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	rs := make([]Role, 0, len(er.roles))
+	for r := range er.roles {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i] < rs[j] })
+	return rs
+}
+
+// registryFile - represents registry file layout.
+type registryFile struct {
+	Departments []Department    `json:"departments"`
+	Roles       []RolePrototype `json:"roles"`
+}
+
+// Load - registers departments and roles decoded from JSON.
+// Whole document is validated first, so on error registry is left unchanged.
+func (er *EmployeeRegistry) Load(r io.Reader) error {
+	var rf registryFile
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rf); err != nil {
+		return fmt.Errorf("decoding registry: %w", err)
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+
+	// validate against registered entries together with the ones staged from document
+	departments := make(map[Department]bool, len(er.departments)+len(rf.Departments))
+	for d := range er.departments {
+		departments[d] = true
+	}
+	roles := make(map[Role]RolePrototype, len(er.roles)+len(rf.Roles))
+	for r, p := range er.roles {
+		roles[r] = p
+	}
+	for _, d := range rf.Departments {
+		if err := checkDepartment(d, departments); err != nil {
+			return err
+		}
+		departments[d] = true
+	}
+	for _, p := range rf.Roles {
+		if err := checkRole(p, departments, roles); err != nil {
+			return err
+		}
+		roles[p.Role] = p
+	}
+
+	er.departments, er.roles = departments, roles
+	return nil
+}
+
+// LoadFile - registers departments and roles from JSON file.
+func (er *EmployeeRegistry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return er.Load(f)
+}
+
+// DefaultEmployeeRegistry - registry with built in departments and roles, used by prototype factory.
+var DefaultEmployeeRegistry = newDefaultEmployeeRegistry()
+
+// newDefaultEmployeeRegistry - creates registry with built in departments and roles.
+func newDefaultEmployeeRegistry() *EmployeeRegistry {
+	er := NewEmployeeRegistry()
+	_ = er.RegisterDepartment(DepartmentMarketing)
+	_ = er.RegisterDepartment(DepartmentEngineering)
+	_ = er.RegisterRole(RolePrototype{
+		Role:       RoleMarketingAnalyst,
+		Department: DepartmentMarketing,
+		Level:      "middle",
+		Team:       "growth",
+		Salary:     SalaryBand{Min: 50000, Max: 70000, Currency: "USD"},
+	})
+	_ = er.RegisterRole(RolePrototype{
+		Role:       RoleSoftwareEngineer,
+		Department: DepartmentEngineering,
+		Level:      "middle",
+		Team:       "platform",
+		Salary:     SalaryBand{Min: 90000, Max: 130000, Currency: "USD"},
+	})
+	return er
+}
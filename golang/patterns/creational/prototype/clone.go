@@ -0,0 +1,194 @@
+package prototype
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// -- Copy through Reflection
+
+// Cloner - represents type providing its own deep copy, DeepClone uses it instead of reflection.
+type Cloner interface {
+	Clone() interface{}
+}
+
+// clonerType - reflected Cloner interface.
+var clonerType = reflect.TypeOf((*Cloner)(nil)).Elem()
+
+// DeepClone - deeply copies any value including unexported fields, preserves shared and cyclic references.
+//
+// Struct fields tagged `clone:"-"` are left zero, fields tagged `clone:"shallow"` are shared with source.
+func DeepClone(src interface{}) interface{} {
+	if src == nil {
+		return nil
+	}
+	c := &deepCloner{visited: make(map[visitKey]reflect.Value)}
+	v := reflect.ValueOf(src)
+	return c.clone(addressable(v)).Interface()
+}
+
+// visitKey - identifies reference already cloned.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int // slices sharing backing array are aliased only when lengths match
+}
+
+// deepCloner - represents single deep clone run.
+type deepCloner struct {
+	visited map[visitKey]reflect.Value
+}
+
+// clone - returns deep copy of v, v must not be read-only.
+func (c *deepCloner) clone(v reflect.Value) reflect.Value {
+	if dst, ok := c.cloneWithHook(v); ok {
+		return dst
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return c.clonePtr(v)
+	case reflect.Interface:
+		dst := reflect.New(v.Type()).Elem()
+		if !v.IsNil() {
+			dst.Set(c.clone(addressable(v.Elem())))
+		}
+		return dst
+	case reflect.Struct:
+		return c.cloneStruct(v)
+	case reflect.Slice:
+		return c.cloneSlice(v)
+	case reflect.Array:
+		dst := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return dst
+	case reflect.Map:
+		return c.cloneMap(v)
+	default:
+		// basic kinds are copied by value, funcs, channels and unsafe pointers are shared
+		dst := reflect.New(v.Type()).Elem()
+		dst.Set(v)
+		return dst
+	}
+}
+
+// cloneWithHook - clones value through Cloner when type implements it.
+func (c *deepCloner) cloneWithHook(v reflect.Value) (reflect.Value, bool) {
+	if !v.Type().Implements(clonerType) || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return reflect.Value{}, false
+	}
+	if v.Kind() == reflect.Interface {
+		return reflect.Value{}, false // hook is applied to dynamic value
+	}
+	cp := v.Interface().(Cloner).Clone()
+	out := reflect.ValueOf(cp)
+	if !out.IsValid() || !out.Type().AssignableTo(v.Type()) {
+		panic(fmt.Sprintf("prototype: %s.Clone returned %T, expected %s", v.Type(), cp, v.Type()))
+	}
+	dst := reflect.New(v.Type()).Elem()
+	dst.Set(out)
+	return dst, true
+}
+
+// clonePtr - clones pointer, every pointer is cloned once to keep aliasing and break cycles.
+func (c *deepCloner) clonePtr(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if dst, ok := c.visited[key]; ok {
+		return dst
+	}
+	dst := reflect.New(v.Type().Elem())
+	c.visited[key] = dst
+	dst.Elem().Set(c.clone(v.Elem()))
+	return dst
+}
+
+// cloneStruct - clones struct field by field including unexported ones.
+func (c *deepCloner) cloneStruct(v reflect.Value) reflect.Value {
+	// time.Time keeps location pointer which must stay shared
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		dst := reflect.New(v.Type()).Elem()
+		dst.Set(v)
+		return dst
+	}
+
+	v = addressable(v)
+	dst := reflect.New(v.Type()).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		src, out := unlock(v.Field(i)), unlock(dst.Field(i))
+		switch sf.Tag.Get("clone") {
+		case "-":
+			continue
+		case "shallow":
+			out.Set(src)
+		default:
+			out.Set(c.clone(src))
+		}
+	}
+	return dst
+}
+
+// cloneSlice - clones slice, slices with same backing array and length stay shared.
+func (c *deepCloner) cloneSlice(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+	key := visitKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}
+	if dst, ok := c.visited[key]; ok {
+		return dst
+	}
+	dst := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+	c.visited[key] = dst
+	for i := 0; i < v.Len(); i++ {
+		dst.Index(i).Set(c.clone(v.Index(i)))
+	}
+	return dst
+}
+
+// cloneMap - clones map keys and values, shared maps stay shared.
+func (c *deepCloner) cloneMap(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if dst, ok := c.visited[key]; ok {
+		return dst
+	}
+	dst := reflect.MakeMapWithSize(v.Type(), v.Len())
+	c.visited[key] = dst
+	iter := v.MapRange()
+	for iter.Next() {
+		dst.SetMapIndex(c.clone(addressable(iter.Key())), c.clone(addressable(iter.Value())))
+	}
+	return dst
+}
+
+// addressable - returns addressable copy of value when it isn't addressable already.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+// unlock - makes addressable unexported field readable and settable.
+func unlock(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// CopyReflection - deeply copying Person object using reflection.
+func (p *Person) CopyReflection() *Person {
+	return DeepClone(p).(*Person)
+}
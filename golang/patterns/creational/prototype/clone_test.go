@@ -0,0 +1,47 @@
+package prototype
+
+import (
+	"testing"
+)
+
+// benchPerson - returns person copied by every benchmark.
+func benchPerson() *Person {
+	return NewPerson("John", NewAddress("London", "Baker Street"), []string{"Chris", "Matt", "Sam"})
+}
+
+func BenchmarkDeepClone(b *testing.B) {
+	p := benchPerson()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = DeepClone(p).(*Person)
+	}
+}
+
+func BenchmarkSerializeCopy(b *testing.B) {
+	p := benchPerson()
+	for _, bm := range []struct {
+		name string
+		copy func() (*Person, error)
+	}{
+		{"json", p.CopySerializationJSON},
+		{"gob", p.CopySerializationBin},
+		{"binary", func() (*Person, error) { return CloneWith(BinaryCodec{}, p) }},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := bm.copy(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkManualCopy(b *testing.B) {
+	p := benchPerson()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = p.Copy()
+	}
+}
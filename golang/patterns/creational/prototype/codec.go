@@ -0,0 +1,235 @@
+package prototype
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// -- Serialization Codecs
+
+// Codec - represents Person serialization format.
+type Codec interface {
+	Encode(w io.Writer, p *Person) error
+	Decode(r io.Reader) (*Person, error)
+}
+
+// JSONCodec - represents JSON codec.
+type JSONCodec struct{}
+
+// Encode - writes person as JSON (implements Codec).
+func (JSONCodec) Encode(w io.Writer, p *Person) error {
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		return fmt.Errorf("json encode: %w", err)
+	}
+	return nil
+}
+
+// Decode - reads person from JSON (implements Codec).
+func (JSONCodec) Decode(r io.Reader) (*Person, error) {
+	var p Person
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("json decode: %w", err)
+	}
+	return &p, nil
+}
+
+// GobCodec - represents gob codec.
+type GobCodec struct{}
+
+// Encode - writes person as gob (implements Codec).
+func (GobCodec) Encode(w io.Writer, p *Person) error {
+	if err := gob.NewEncoder(w).Encode(p); err != nil {
+		return fmt.Errorf("gob encode: %w", err)
+	}
+	return nil
+}
+
+// Decode - reads person from gob (implements Codec).
+func (GobCodec) Decode(r io.Reader) (*Person, error) {
+	var p Person
+	if err := gob.NewDecoder(r).Decode(&p); err != nil {
+		return nil, fmt.Errorf("gob decode: %w", err)
+	}
+	return &p, nil
+}
+
+// BinaryCodec - represents compact binary codec.
+//
+// Layout: magic "PRS1", name, address flag (0 - nil, 1 - present) followed by city and street,
+// friends count + 1 (0 - nil slice) followed by friends. Strings are uvarint length prefixed.
+type BinaryCodec struct{}
+
+// binaryMagic - identifies binary encoded person.
+var binaryMagic = []byte("PRS1")
+
+// maxBinaryLen - limits decoded strings and slices to protect from corrupted input.
+const maxBinaryLen = 1 << 20
+
+// Encode - writes person in compact binary form (implements Codec).
+func (BinaryCodec) Encode(w io.Writer, p *Person) error {
+	bs := append([]byte{}, binaryMagic...)
+	appendString := func(s string) {
+		bs = appendUvarint(bs, uint64(len(s)))
+		bs = append(bs, s...)
+	}
+
+	appendString(p.Name)
+	if p.Address == nil {
+		bs = append(bs, 0)
+	} else {
+		bs = append(bs, 1)
+		appendString(p.Address.City)
+		appendString(p.Address.Street)
+	}
+	if p.Friends == nil {
+		bs = appendUvarint(bs, 0)
+	} else {
+		bs = appendUvarint(bs, uint64(len(p.Friends))+1)
+		for _, f := range p.Friends {
+			appendString(f)
+		}
+	}
+
+	if _, err := w.Write(bs); err != nil {
+		return fmt.Errorf("binary encode: %w", err)
+	}
+	return nil
+}
+
+// appendUvarint - appends uvarint encoded number.
+func appendUvarint(bs []byte, n uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(bs, buf[:binary.PutUvarint(buf, n)]...)
+}
+
+// byteReader - represents reader binary codec decodes from.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// Decode - reads person from compact binary form (implements Codec).
+// Readers not implementing io.ByteReader are buffered and may be read past the encoded person.
+func (BinaryCodec) Decode(r io.Reader) (*Person, error) {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	readUvarint := func() (uint64, error) {
+		n, err := binary.ReadUvarint(br)
+		if err == nil && n > maxBinaryLen {
+			err = fmt.Errorf("length %d exceeds limit", n)
+		}
+		return n, err
+	}
+	readString := func() (string, error) {
+		n, err := readUvarint()
+		if err != nil {
+			return "", err
+		}
+		bs := make([]byte, n)
+		_, err = io.ReadFull(br, bs)
+		return string(bs), err
+	}
+
+	p, err := func() (*Person, error) {
+		magic := make([]byte, len(binaryMagic))
+		if _, err := io.ReadFull(br, magic); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(magic, binaryMagic) {
+			return nil, errors.New("invalid magic")
+		}
+
+		var p Person
+		var err error
+		if p.Name, err = readString(); err != nil {
+			return nil, err
+		}
+		flag, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch flag {
+		case 0:
+		case 1:
+			p.Address = &Address{}
+			if p.Address.City, err = readString(); err != nil {
+				return nil, err
+			}
+			if p.Address.Street, err = readString(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid address flag %d", flag)
+		}
+		n, err := readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			p.Friends = make([]string, 0, n-1)
+			for i := uint64(1); i < n; i++ {
+				f, err := readString()
+				if err != nil {
+					return nil, err
+				}
+				p.Friends = append(p.Friends, f)
+			}
+		}
+		return &p, nil
+	}()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("binary decode: %w", err)
+	}
+	return p, nil
+}
+
+// CloneWith - deeply copying Person object by encoding and decoding it with provided codec.
+func CloneWith(c Codec, p *Person) (*Person, error) {
+	buff := new(bytes.Buffer)
+	if err := c.Encode(buff, p); err != nil {
+		return nil, err
+	}
+	return c.Decode(buff)
+}
+
+// SavePrototype - persists prototype to file, file is replaced atomically.
+func SavePrototype(path string, c Codec, p *Person) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := c.Encode(tmp, p); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadPrototype - reads prototype persisted by SavePrototype.
+func LoadPrototype(path string, c Codec) (*Person, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return c.Decode(f)
+}
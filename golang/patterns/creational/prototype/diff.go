@@ -0,0 +1,384 @@
+package prototype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// -- Diff and Patch
+
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// PatchOp - represents RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string      // add, remove, replace, move, copy or test
+	Path  string      // JSON pointer (RFC 6901)
+	From  string      // source pointer of move and copy
+	Value interface{} // value of add, replace and test
+
+	old interface{} // replaced or removed value, used by diff report
+}
+
+// MarshalJSON - encodes operation, value is kept even when null (implements json.Marshaler).
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"op": op.Op, "path": op.Path}
+	switch op.Op {
+	case "add", "replace", "test":
+		m["value"] = op.Value
+	case "move", "copy":
+		m["from"] = op.From
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON - decodes operation (implements json.Unmarshaler).
+func (op *PatchOp) UnmarshalJSON(bs []byte) error {
+	var raw struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		From  string      `json:"from"`
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(bs, &raw); err != nil {
+		return err
+	}
+	*op = PatchOp{Op: raw.Op, Path: raw.Path, From: raw.From, Value: raw.Value}
+	return nil
+}
+
+// Patch - represents RFC 6902 JSON Patch document.
+type Patch []PatchOp
+
+// Diff - compares JSON representations of two values and returns patch turning from into to.
+func Diff(from, to interface{}) (Patch, error) {
+	a, err := toTree(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toTree(to)
+	if err != nil {
+		return nil, err
+	}
+	var patch Patch
+	diffTree("", a, b, &patch)
+	return patch, nil
+}
+
+// diffTree - appends operations turning a into b at provided pointer.
+func diffTree(path string, a, b interface{}, patch *Patch) {
+	switch at := a.(type) {
+	case map[string]interface{}:
+		bt, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(at)+len(bt))
+		for k := range at {
+			keys = append(keys, k)
+		}
+		for k := range bt {
+			if _, ok := at[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			av, inA := at[k]
+			bv, inB := bt[k]
+			p := path + "/" + escapePointer(k)
+			switch {
+			case !inB:
+				*patch = append(*patch, PatchOp{Op: "remove", Path: p, old: av})
+			case !inA:
+				*patch = append(*patch, PatchOp{Op: "add", Path: p, Value: bv})
+			default:
+				diffTree(p, av, bv, patch)
+			}
+		}
+		return
+	case []interface{}:
+		bt, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(at)
+		if len(bt) < n {
+			n = len(bt)
+		}
+		for i := 0; i < n; i++ {
+			diffTree(path+"/"+strconv.Itoa(i), at[i], bt[i], patch)
+		}
+		for i := n; i < len(bt); i++ {
+			*patch = append(*patch, PatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: bt[i]})
+		}
+		// remove from the end so indices stay valid
+		for i := len(at) - 1; i >= n; i-- {
+			*patch = append(*patch, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i), old: at[i]})
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*patch = append(*patch, PatchOp{Op: "replace", Path: path, Value: b, old: a})
+	}
+}
+
+// ApplyPatch - applies patch to JSON representation of base and decodes result into out.
+func ApplyPatch(base interface{}, patch Patch, out interface{}) error {
+	doc, err := toTree(base)
+	if err != nil {
+		return err
+	}
+	for i, op := range patch {
+		if doc, err = applyOp(doc, op); err != nil {
+			return fmt.Errorf("%w: operation %d (%s %s): %v", ErrInvalidPatch, i, op.Op, op.Path, err)
+		}
+	}
+	bs, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, out)
+}
+
+// applyOp - applies single operation to document, returns updated document.
+func applyOp(doc interface{}, op PatchOp) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		return setPointer(doc, op.Path, op.Value, true)
+	case "remove":
+		return removePointer(doc, op.Path)
+	case "replace":
+		if _, err := getPointer(doc, op.Path); err != nil {
+			return nil, err
+		}
+		return setPointer(doc, op.Path, op.Value, false)
+	case "move":
+		v, err := getPointer(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.New("can't move value into itself")
+		}
+		if doc, err = removePointer(doc, op.From); err != nil {
+			return nil, err
+		}
+		return setPointer(doc, op.Path, v, true)
+	case "copy":
+		v, err := getPointer(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err = toTree(v) // copy must not share containers with source
+		if err != nil {
+			return nil, err
+		}
+		return setPointer(doc, op.Path, v, true)
+	case "test":
+		v, err := getPointer(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		want, err := toTree(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, want) {
+			return nil, fmt.Errorf("test failed: got %v, want %v", v, want)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// Report - renders patch as human readable diff, one line per operation.
+func (p Patch) Report() string {
+	var sb strings.Builder
+	for _, op := range p {
+		switch op.Op {
+		case "add":
+			fmt.Fprintf(&sb, "+ %s: %s\n", op.Path, formatValue(op.Value))
+		case "remove":
+			if op.old != nil {
+				fmt.Fprintf(&sb, "- %s: %s\n", op.Path, formatValue(op.old))
+			} else {
+				fmt.Fprintf(&sb, "- %s\n", op.Path)
+			}
+		case "replace":
+			fmt.Fprintf(&sb, "~ %s: %s -> %s\n", op.Path, formatValue(op.old), formatValue(op.Value))
+		case "move", "copy":
+			fmt.Fprintf(&sb, "%s %s -> %s\n", op.Op, op.From, op.Path)
+		default:
+			fmt.Fprintf(&sb, "%s %s: %s\n", op.Op, op.Path, formatValue(op.Value))
+		}
+	}
+	return sb.String()
+}
+
+// formatValue - formats value as compact JSON.
+func formatValue(v interface{}) string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bs)
+}
+
+// -- JSON tree helpers
+
+// toTree - converts value into generic JSON tree (maps, slices and scalars).
+func toTree(v interface{}) (interface{}, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(bs, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// escapePointer - escapes JSON pointer reference token.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// splitPointer - splits JSON pointer into unescaped reference tokens.
+func splitPointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// arrayIndex - parses array index token, "-" means past the end when allowed.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if token == "-" && allowEnd {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	max := length - 1
+	if allowEnd {
+		max = length
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// getPointer - returns value referenced by pointer.
+func getPointer(doc interface{}, path string) (interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", t)
+			}
+			cur = v
+		case []interface{}:
+			i, err := arrayIndex(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("can't traverse %q into scalar", t)
+		}
+	}
+	return cur, nil
+}
+
+// setPointer - sets value referenced by pointer, insert adds array element instead of replacing it.
+func setPointer(doc interface{}, path string, value interface{}, insert bool) (interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parentPath := path[:strings.LastIndex(path, "/")]
+	parent, err := getPointer(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(last, len(p), insert)
+		if err != nil {
+			return nil, err
+		}
+		if !insert {
+			p[i] = value
+			return doc, nil
+		}
+		p = append(p, nil)
+		copy(p[i+1:], p[i:])
+		p[i] = value
+		return setPointer(doc, parentPath, p, false)
+	default:
+		return nil, fmt.Errorf("parent of %q is not a container", path)
+	}
+}
+
+// removePointer - removes value referenced by pointer.
+func removePointer(doc interface{}, path string) (interface{}, error) {
+	tokens, err := splitPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("can't remove document root")
+	}
+	parentPath := path[:strings.LastIndex(path, "/")]
+	parent, err := getPointer(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[last]; !ok {
+			return nil, fmt.Errorf("member %q not found", last)
+		}
+		delete(p, last)
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(last, len(p), false)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p[:i:i], p[i+1:]...)
+		return setPointer(doc, parentPath, p, false)
+	default:
+		return nil, fmt.Errorf("parent of %q is not a container", path)
+	}
+}
@@ -1,10 +1,12 @@
 package prototype
 
 import (
-	"bytes"
-	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 )
 
@@ -31,15 +33,115 @@ func Prototype() {
 	p2.Friends = append(p2.Friends, "Mrs Hudson")
 
 	// clone person through serialization
-	// p3 := p2.CopySerializationJSON()
-	p3 := p2.CopySerializationBin()
+	// p3, err := p2.CopySerializationJSON()
+	p3, err := p2.CopySerializationBin()
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 	p3.Address.City = "Birmingham"
 	p3.Friends = append(p3.Friends, "Molly Hooper")
 
+	// clone person using reflection, shared and cyclic references are preserved
+	type node struct {
+		name string
+		next *node
+	}
+	loop := &node{name: "Irene Adler"}
+	loop.next = loop
+	loopCp := DeepClone(loop).(*node)
+	fmt.Printf("cycle preserved: %t | copied: %t\n", loopCp.next == loopCp, loopCp != loop)
+	p5 := p1.CopyReflection()
+	p5.Address.City = "Dartmoor"
+	fmt.Printf("City: %s | Prototype City: %s\n", p5.Address.City, p1.Address.City)
+
+	// diff clone against its prototype
+	patch, err := Diff(p1, p2)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Print(patch.Report())
+	bs, err := json.Marshal(patch)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("JSON Patch: %s\n", bs)
+	var p2r Person
+	if err := ApplyPatch(p1, patch, &p2r); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("patched prototype equals clone: %t\n", reflect.DeepEqual(&p2r, p2))
+
+	// clone person through compact binary codec and persist it as prototype
+	p7, err := CloneWith(BinaryCodec{}, p3)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	dir, err := ioutil.TempDir("", "prototypes")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "birmingham-person.bin")
+	if err := SavePrototype(path, BinaryCodec{}, p7); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	p8, err := LoadPrototype(path, BinaryCodec{})
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("Loaded: %s | City: %s | Friends: %d\n", p8.Name, p8.Address.City, len(p8.Friends))
+	if _, err := (BinaryCodec{}).Decode(strings.NewReader("PRS1\x05Sh")); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
 	// clone person using prototype factory
 	p4 := NewLondonPerson("Sherlock", "Baker Street")
 	p4.Friends = append(p3.Friends, "Greg Lestrade")
 
+	// clone person using prototype registry loaded from file
+	const _path = "./patterns/creational/prototype/files/prototypes.json"
+	reg := NewPrototypeRegistry()
+	if err := reg.LoadFile(_path); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	if _, err := reg.Register("paris-person", NewPerson("", NewAddress("Paris", "Rue de Rivoli"), nil)); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	for _, info := range reg.List() {
+		fmt.Printf("prototype: %s v%d\n", info.Name, info.LatestVersion)
+	}
+	p6, err := reg.CloneVersion("paris-person", 1, WithName("Arsene Lupin"), WithStreet("Rue Lepic"))
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("Name: %s | City: %s | Street: %s\n", p6.Name, p6.Address.City, p6.Address.Street)
+	p6.Friends = append(p6.Friends, "Isidore Beautrelet")
+	stored, err := reg.Derive("paris-person", 1, p6)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	p6r, err := reg.ClonePatched("paris-person", 1, stored)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("stored as prototype + %d operations, restored: %t\n", len(stored), reflect.DeepEqual(p6r, p6))
+	if _, err := reg.Clone("berlin-person"); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
 	fmt.Printf(
 		"Name: %s | City: %s | Street: %s | Friends: %s\n",
 		p1.Name, p1.Address.City, p1.Address.Street, strings.Join(p1.Friends, ", "),
@@ -94,39 +196,22 @@ func (a *Address) Copy() *Address {
 
 // -- Copy through Serialization
 
-// CopySerializationJSON - deeply copying Person object using serialization.
-func (p *Person) CopySerializationJSON() *Person {
-	bs, _ := json.Marshal(p)
-	var cp Person
-	_ = json.Unmarshal(bs, &cp)
-	return &cp
+// CopySerializationJSON - deeply copying Person object using JSON serialization.
+func (p *Person) CopySerializationJSON() (*Person, error) {
+	return CloneWith(JSONCodec{}, p)
 }
 
-// CopySerializationBin - deeply copying Person object using serialization.
-func (p *Person) CopySerializationBin() *Person {
-	buff := new(bytes.Buffer)
-	enc := gob.NewEncoder(buff)
-	_ = enc.Encode(p)
-
-	dec := gob.NewDecoder(buff)
-	var cp Person
-	_ = dec.Decode(&cp)
-
-	return &cp
+// CopySerializationBin - deeply copying Person object using gob serialization.
+func (p *Person) CopySerializationBin() (*Person, error) {
+	return CloneWith(GobCodec{}, p)
 }
 
 // -- Prototype Factory
 
+// LondonPerson - london person prototype, changes are picked up by NewLondonPerson.
 var LondonPerson = Person{Address: &Address{City: "London"}}
 
-func newPersonFromProto(proto *Person, name string, street string) *Person {
-	cp := proto.CopySerializationJSON()
-	cp.Name = name
-	cp.Address.Street = street
-	return cp
-}
-
-// NewLondonPerson - creates instance of Person.
+// NewLondonPerson - creates instance of Person from LondonPerson prototype.
 func NewLondonPerson(name, street string) *Person {
-	return newPersonFromProto(&LondonPerson, name, street)
+	return clonePerson(&LondonPerson, WithName(name), WithStreet(street))
 }
@@ -0,0 +1,209 @@
+package prototype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// -- Prototype Registry
+
+var (
+	ErrUnknownPrototype = errors.New("unknown prototype")
+	ErrUnknownVersion   = errors.New("unknown prototype version")
+)
+
+// PersonOverride - represents modification applied to cloned prototype.
+type PersonOverride func(p *Person)
+
+// WithName - overrides name of cloned person.
+func WithName(name string) PersonOverride {
+	return func(p *Person) { p.Name = name }
+}
+
+// WithCity - overrides city of cloned person.
+func WithCity(city string) PersonOverride {
+	return func(p *Person) {
+		if p.Address == nil {
+			p.Address = &Address{}
+		}
+		p.Address.City = city
+	}
+}
+
+// WithStreet - overrides street of cloned person.
+func WithStreet(street string) PersonOverride {
+	return func(p *Person) {
+		if p.Address == nil {
+			p.Address = &Address{}
+		}
+		p.Address.Street = street
+	}
+}
+
+// WithFriends - appends friends to cloned person.
+func WithFriends(friends ...string) PersonOverride {
+	return func(p *Person) { p.Friends = append(p.Friends, friends...) }
+}
+
+// PrototypeInfo - describes registered prototype.
+type PrototypeInfo struct {
+	Name          string
+	LatestVersion int
+}
+
+// PrototypeRegistry - represents goroutine safe registry of named, versioned Person prototypes.
+type PrototypeRegistry struct {
+	mu         sync.RWMutex
+	prototypes map[string][]*Person // version N is stored at index N-1
+}
+
+// NewPrototypeRegistry - creates new instance of PrototypeRegistry.
+func NewPrototypeRegistry() *PrototypeRegistry {
+	return &PrototypeRegistry{prototypes: make(map[string][]*Person)}
+}
+
+// Register - stores copy of prototype as its next version, returns registered version.
+func (pr *PrototypeRegistry) Register(name string, p *Person) (int, error) {
+	cp, err := prepare(name, p)
+	if err != nil {
+		return 0, err
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.prototypes[name] = append(pr.prototypes[name], cp)
+	return len(pr.prototypes[name]), nil
+}
+
+// prepare - validates prototype and returns copy of it to be stored.
+func prepare(name string, p *Person) (*Person, error) {
+	if name == "" {
+		return nil, errors.New("prototype name is required")
+	}
+	if p == nil {
+		return nil, fmt.Errorf("prototype %q: nil person", name)
+	}
+	return DeepClone(p).(*Person), nil
+}
+
+// Clone - clones latest version of prototype and applies overrides.
+func (pr *PrototypeRegistry) Clone(name string, overrides ...PersonOverride) (*Person, error) {
+	return pr.CloneVersion(name, 0, overrides...)
+}
+
+// CloneVersion - clones provided version of prototype (0 - latest) and applies overrides.
+func (pr *PrototypeRegistry) CloneVersion(name string, version int, overrides ...PersonOverride) (*Person, error) {
+	pr.mu.RLock()
+	versions, ok := pr.prototypes[name]
+	pr.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPrototype, name)
+	}
+	if version == 0 {
+		version = len(versions)
+	}
+	if version < 0 || version > len(versions) {
+		return nil, fmt.Errorf("%w: %q v%d", ErrUnknownVersion, name, version)
+	}
+
+	// stored prototypes are never mutated, so cloning doesn't need the lock
+	return clonePerson(versions[version-1], overrides...), nil
+}
+
+// clonePerson - deeply copies person and applies overrides.
+func clonePerson(p *Person, overrides ...PersonOverride) *Person {
+	cp := DeepClone(p).(*Person)
+	for _, override := range overrides {
+		override(cp)
+	}
+	return cp
+}
+
+// Derive - returns patch describing customizations of clone relative to provided prototype version (0 - latest).
+func (pr *PrototypeRegistry) Derive(name string, version int, clone *Person) (Patch, error) {
+	proto, err := pr.CloneVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(proto, clone)
+}
+
+// ClonePatched - clones provided prototype version (0 - latest) and applies patch, restores clone stored as prototype + patch.
+func (pr *PrototypeRegistry) ClonePatched(name string, version int, patch Patch) (*Person, error) {
+	proto, err := pr.CloneVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	var p Person
+	if err := ApplyPatch(proto, patch, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List - describes every registered prototype ordered by name.
+func (pr *PrototypeRegistry) List() []PrototypeInfo {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	infos := make([]PrototypeInfo, 0, len(pr.prototypes))
+	for name, versions := range pr.prototypes {
+		infos = append(infos, PrototypeInfo{Name: name, LatestVersion: len(versions)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Load - registers prototypes decoded from JSON object keyed by prototype name,
+// either all of them or none when any is invalid.
+func (pr *PrototypeRegistry) Load(r io.Reader) error {
+	var prototypes map[string]*Person
+	if err := json.NewDecoder(r).Decode(&prototypes); err != nil {
+		return fmt.Errorf("decoding prototypes: %w", err)
+	}
+	names := make([]string, 0, len(prototypes))
+	for name := range prototypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	copies := make([]*Person, 0, len(names))
+	for _, name := range names {
+		cp, err := prepare(name, prototypes[name])
+		if err != nil {
+			return err
+		}
+		copies = append(copies, cp)
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	for i, name := range names {
+		pr.prototypes[name] = append(pr.prototypes[name], copies[i])
+	}
+	return nil
+}
+
+// LoadFile - registers prototypes from JSON file.
+func (pr *PrototypeRegistry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return pr.Load(f)
+}
+
+// DefaultPrototypeRegistry - registry of built in prototypes, "london-person" is a copy of LondonPerson
+// taken at package init, later changes of the variable are seen by NewLondonPerson only.
+var DefaultPrototypeRegistry = newDefaultPrototypeRegistry()
+
+// newDefaultPrototypeRegistry - creates registry with built in prototypes.
+func newDefaultPrototypeRegistry() *PrototypeRegistry {
+	pr := NewPrototypeRegistry()
+	_, _ = pr.Register("london-person", &LondonPerson)
+	return pr
+}
@@ -0,0 +1,190 @@
+package prototype
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parisPerson - returns prototype registered by tests.
+func parisPerson() *Person {
+	return NewPerson("", NewAddress("Paris", ""), []string{"Louvre"})
+}
+
+// errorOf - drops result and returns error only.
+func errorOf(_ interface{}, err error) error {
+	return err
+}
+
+func TestPrototypeRegistryVersions(t *testing.T) {
+	pr := NewPrototypeRegistry()
+	for i, city := range []string{"Paris", "Lyon"} {
+		v, err := pr.Register("french-person", NewPerson("", NewAddress(city, ""), nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v != i+1 {
+			t.Errorf("expected version %d, got %d", i+1, v)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		city    string
+		err     error
+	}{
+		{"french-person", 0, "Lyon", nil},
+		{"french-person", 1, "Paris", nil},
+		{"french-person", 2, "Lyon", nil},
+		{"french-person", 3, "", ErrUnknownVersion},
+		{"french-person", -1, "", ErrUnknownVersion},
+		{"german-person", 0, "", ErrUnknownPrototype},
+	}
+	for _, tt := range tests {
+		p, err := pr.CloneVersion(tt.name, tt.version)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s v%d: expected error %v, got %v", tt.name, tt.version, tt.err, err)
+			continue
+		}
+		if err == nil && p.Address.City != tt.city {
+			t.Errorf("%s v%d: expected city %s, got %s", tt.name, tt.version, tt.city, p.Address.City)
+		}
+	}
+
+	if _, err := pr.Register("", parisPerson()); err == nil {
+		t.Error("expected error for missing name")
+	}
+	if _, err := pr.Register("nil-person", nil); err == nil {
+		t.Error("expected error for nil person")
+	}
+	if got, want := pr.List(), []PrototypeInfo{{"french-person", 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPrototypeRegistryIsolation(t *testing.T) {
+	pr := NewPrototypeRegistry()
+	p := parisPerson()
+	if _, err := pr.Register("paris-person", p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// neither registered person nor clones share memory with stored prototype
+	p.Address.City, p.Friends[0] = "Nice", "Orsay"
+	clone, err := pr.Clone("paris-person", WithName("Jean"), WithStreet("Rivoli"), WithFriends("Marie"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := NewPerson("Jean", NewAddress("Paris", "Rivoli"), []string{"Louvre", "Marie"})
+	if !reflect.DeepEqual(clone, want) {
+		t.Errorf("expected %+v, got %+v", want, clone)
+	}
+	clone.Address.City, clone.Friends[0] = "Nice", "Orsay"
+
+	got, err := pr.Clone("paris-person")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, parisPerson()) {
+		t.Errorf("expected stored prototype %+v, got %+v", parisPerson(), got)
+	}
+}
+
+func TestPrototypeRegistryLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		err   string
+		infos []PrototypeInfo
+	}{
+		{"all valid", `{"a":{"Name":"A"},"b":{"Name":"B"}}`, "", []PrototypeInfo{{"a", 2}, {"b", 1}}},
+		{"null prototype", `{"a":{"Name":"A"},"b":null}`, `prototype "b": nil person`, []PrototypeInfo{{"a", 1}}},
+		{"empty name", `{"":{"Name":"A"},"b":{"Name":"B"}}`, "prototype name is required", []PrototypeInfo{{"a", 1}}},
+		{"malformed", `{"a":{"Name":"A"},`, "decoding prototypes", []PrototypeInfo{{"a", 1}}},
+	}
+	for _, tt := range tests {
+		pr := NewPrototypeRegistry()
+		if _, err := pr.Register("a", &Person{Name: "A"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := pr.Load(strings.NewReader(tt.json))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+		// failed load registers nothing
+		if got := pr.List(); !reflect.DeepEqual(got, tt.infos) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.infos, got)
+		}
+	}
+
+	pr := NewPrototypeRegistry()
+	if err := pr.LoadFile("files/prototypes.json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []PrototypeInfo{{"london-person", 1}, {"paris-person", 1}}; !reflect.DeepEqual(pr.List(), want) {
+		t.Errorf("expected %v, got %v", want, pr.List())
+	}
+}
+
+func TestPrototypeRegistryDerive(t *testing.T) {
+	pr := NewPrototypeRegistry()
+	if _, err := pr.Register("paris-person", parisPerson()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clone, err := pr.Clone("paris-person", WithName("Jean"), WithStreet("Rivoli"), WithFriends("Marie"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	patch, err := pr.Derive("paris-person", 0, clone)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ops := make([]string, 0, len(patch))
+	for _, op := range patch {
+		ops = append(ops, op.Op+" "+op.Path)
+	}
+	want := []string{"replace /Address/Street", "add /Friends/1", "replace /Name"}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("expected patch %v, got %v", want, ops)
+	}
+
+	// patch keeps restoring clone from version it was derived from after new version is registered
+	if _, err := pr.Register("paris-person", NewPerson("", NewAddress("Lyon", ""), nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored, err := pr.ClonePatched("paris-person", 1, patch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(restored, clone) {
+		t.Errorf("expected %+v, got %+v", clone, restored)
+	}
+	latest, err := pr.ClonePatched("paris-person", 0, Patch{{Op: "replace", Path: "/Name", Value: "Paul"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest.Name != "Paul" || latest.Address.City != "Lyon" {
+		t.Errorf("expected Paul from Lyon, got %+v", latest)
+	}
+
+	errs := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"derive unknown prototype", errorOf(pr.Derive("german-person", 0, clone)), ErrUnknownPrototype},
+		{"derive unknown version", errorOf(pr.Derive("paris-person", 3, clone)), ErrUnknownVersion},
+		{"clone patched unknown version", errorOf(pr.ClonePatched("paris-person", 3, patch)), ErrUnknownVersion},
+		{"clone patched invalid patch", errorOf(pr.ClonePatched("paris-person", 1, Patch{{Op: "remove", Path: "/Missing"}})), ErrInvalidPatch},
+	}
+	for _, tt := range errs {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.err)
+		}
+	}
+}
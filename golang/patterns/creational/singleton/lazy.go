@@ -0,0 +1,253 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// -- Lazy Singleton
+
+var (
+	ErrInitPanic = errors.New("initializer panicked")
+	ErrDiscarded = errors.New("lazy instance discarded")
+)
+
+// Initializer - constructs instance, returned error allows construction to be retried later.
+type Initializer func(ctx context.Context) (interface{}, error)
+
+// Backoff - represents exponential delay between failed initialization attempts.
+type Backoff struct {
+	Initial time.Duration // delay after first failure
+	Max     time.Duration // upper bound of delay, 0 - unbounded
+	Factor  float64       // delay multiplier applied after every next failure, defaults to 2
+}
+
+// DefaultBackoff - backoff used when none is provided.
+var DefaultBackoff = Backoff{Initial: 100 * time.Millisecond, Max: 10 * time.Second, Factor: 2}
+
+// delay - returns delay before next attempt after provided number of consecutive failures.
+func (b Backoff) delay(failures int) time.Duration {
+	factor := b.Factor
+	if factor <= 1 {
+		factor = 2
+	}
+	d := float64(b.Initial)
+	for i := 1; i < failures; i++ {
+		d *= factor
+		if b.Max > 0 && d >= float64(b.Max) {
+			return b.Max
+		}
+	}
+	return time.Duration(d)
+}
+
+// InitError - represents failed initialization, calls made before RetryAt fail fast with it.
+type InitError struct {
+	Attempts int       // consecutive failed attempts
+	RetryAt  time.Time // next attempt is not made before this time
+	Err      error     // error of last attempt
+}
+
+// Error - formats error (implements error).
+func (e *InitError) Error() string {
+	return fmt.Sprintf("initialization failed after %d attempt(s), retry after %s: %v",
+		e.Attempts, e.RetryAt.Format(time.RFC3339Nano), e.Err)
+}
+
+// Unwrap - returns error of last attempt.
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+// inflight - represents initialization in progress, done is closed once it's finished.
+type inflight struct {
+	done chan struct{}
+}
+
+// Lazy - represents lazily constructed instance whose initialization may fail and be retried.
+// Concurrent callers share single initialization; once it succeeds the instance never changes.
+type Lazy struct {
+	init    Initializer
+	backoff Backoff
+
+	mu        sync.Mutex
+	value     interface{}
+	ready     bool
+	running   *inflight
+	cancel    context.CancelFunc // cancels running initialization
+	lastErr   *InitError
+	discarded bool
+}
+
+// NewLazy - creates new instance of Lazy.
+func NewLazy(init Initializer, backoff Backoff) *Lazy {
+	return &Lazy{init: init, backoff: backoff}
+}
+
+// Get - returns instance, constructing it on first call or once backoff after failure has elapsed.
+// Cancelling ctx stops waiting; cancelled attempts aren't counted as failures.
+func (l *Lazy) Get(ctx context.Context) (interface{}, error) {
+	for {
+		l.mu.Lock()
+		if l.discarded {
+			l.mu.Unlock()
+			return nil, ErrDiscarded
+		}
+		if l.ready {
+			l.mu.Unlock()
+			return l.value, nil
+		}
+		if running := l.running; running != nil {
+			// somebody else is initializing, wait for the outcome and look again
+			l.mu.Unlock()
+			select {
+			case <-running.done:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if l.lastErr != nil && time.Now().Before(l.lastErr.RetryAt) {
+			err := l.lastErr
+			l.mu.Unlock()
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+		running := &inflight{done: make(chan struct{})}
+		runCtx, cancel := context.WithCancel(ctx)
+		l.running, l.cancel = running, cancel
+		l.mu.Unlock()
+
+		value, err := l.run(runCtx)
+		cancel()
+
+		l.mu.Lock()
+		l.running, l.cancel = nil, nil
+		switch {
+		case l.discarded:
+			// discarded while initializing, nobody will use the instance
+			if err == nil {
+				closeValue(value)
+			}
+			value, err = nil, ErrDiscarded
+		case err == nil:
+			l.value, l.ready, l.lastErr = value, true, nil
+		case ctx.Err() != nil:
+			// cancelled attempt tells nothing about the initializer, let waiters try again
+			err = ctx.Err()
+		default:
+			attempts := 1
+			if l.lastErr != nil {
+				attempts = l.lastErr.Attempts + 1
+			}
+			l.lastErr = &InitError{Attempts: attempts, RetryAt: time.Now().Add(l.backoff.delay(attempts)), Err: err}
+			err = l.lastErr
+		}
+		l.mu.Unlock()
+		close(running.done)
+		return value, err
+	}
+}
+
+// run - calls initializer, panics are reported as errors so waiters are never stuck.
+func (l *Lazy) run(ctx context.Context) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("%w: %v", ErrInitPanic, r)
+		}
+	}()
+	return l.init(ctx)
+}
+
+// Value - returns instance without constructing it, reports whether it has been constructed.
+func (l *Lazy) Value() (interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.value, l.ready
+}
+
+// Discard - drops lazy for good: running initialization is cancelled, constructed instance
+// (or the one finishing initialization later) is closed if it's io.Closer, next calls fail with ErrDiscarded.
+func (l *Lazy) Discard() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.discarded {
+		return
+	}
+	l.discarded = true
+	if l.cancel != nil {
+		l.cancel()
+	}
+	if l.ready {
+		closeValue(l.value)
+		l.value, l.ready = nil, false
+	}
+}
+
+// closeValue - releases resources of instance which is no longer used.
+func closeValue(v interface{}) {
+	if c, ok := v.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
+// Ready - reports whether instance has been constructed.
+func (l *Lazy) Ready() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ready
+}
+
+// -- Multiton
+
+// KeyedInitializer - constructs instance for provided key.
+type KeyedInitializer func(ctx context.Context, key string) (interface{}, error)
+
+// Multiton - represents lazily constructed instances, one per key (e.g. per region database).
+// Each key is initialized, retried and backed off independently.
+type Multiton struct {
+	init    KeyedInitializer
+	backoff Backoff
+
+	mu        sync.Mutex
+	instances map[string]*Lazy
+}
+
+// NewMultiton - creates new instance of Multiton.
+func NewMultiton(init KeyedInitializer, backoff Backoff) *Multiton {
+	return &Multiton{init: init, backoff: backoff, instances: make(map[string]*Lazy)}
+}
+
+// Get - returns instance for provided key, constructing it when needed.
+func (m *Multiton) Get(ctx context.Context, key string) (interface{}, error) {
+	m.mu.Lock()
+	l, ok := m.instances[key]
+	if !ok {
+		l = NewLazy(func(ctx context.Context) (interface{}, error) { return m.init(ctx, key) }, m.backoff)
+		m.instances[key] = l
+	}
+	m.mu.Unlock()
+	return l.Get(ctx)
+}
+
+// Keys - returns keys of constructed instances in order.
+func (m *Multiton) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.instances))
+	for key, l := range m.instances {
+		if l.Ready() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package singleton
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// -- Data Loading
+
+// LoadCities - loads cities from CSV ("name,population" with header) or JSON file.
+func LoadCities(path string) ([]City, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading cities: %w", err)
+	}
+	defer f.Close()
+
+	var cities []City
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		cities, err = decodeCitiesCSV(f)
+	case ".json":
+		cities, err = decodeCitiesJSON(f)
+	default:
+		err = fmt.Errorf("unsupported format %q", ext)
+	}
+	if err == nil {
+		err = validateCities(cities)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading cities from %s: %w", path, err)
+	}
+	return cities, nil
+}
+
+// decodeCitiesCSV - decodes cities from CSV.
+func decodeCitiesCSV(r io.Reader) ([]City, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || !strings.EqualFold(records[0][0], "name") {
+		return nil, fmt.Errorf("missing \"name,population\" header")
+	}
+
+	cities := make([]City, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		population, err := strconv.Atoi(strings.ReplaceAll(rec[1], ",", ""))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid population %q", i+2, rec[1])
+		}
+		cities = append(cities, City{Name: rec[0], Population: population})
+	}
+	return cities, nil
+}
+
+// decodeCitiesJSON - decodes cities from JSON array.
+func decodeCitiesJSON(r io.Reader) ([]City, error) {
+	var cities []City
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cities); err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+// validateCities - rejects unnamed, duplicated cities and negative populations.
+func validateCities(cities []City) error {
+	seen := make(map[string]bool, len(cities))
+	for i, c := range cities {
+		switch {
+		case strings.TrimSpace(c.Name) == "":
+			return fmt.Errorf("city %d: name is required", i+1)
+		case c.Population < 0:
+			return fmt.Errorf("city %q: negative population %d", c.Name, c.Population)
+		case seen[c.Name]:
+			return fmt.Errorf("city %q: duplicated", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return nil
+}
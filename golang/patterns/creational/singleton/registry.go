@@ -0,0 +1,173 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// -- Singleton Registry
+
+var ErrUnknownInstance = errors.New("unknown singleton")
+
+// RepositoryInitializer - constructs repository registered under a name.
+type RepositoryInitializer func(ctx context.Context) (Repository, error)
+
+// Cleaner - represents scope which runs registered functions when it ends, satisfied by *testing.T.
+type Cleaner interface {
+	Cleanup(fn func())
+}
+
+// registryEntry - represents named singleton and overrides stacked on top of it.
+type registryEntry struct {
+	init      RepositoryInitializer
+	lazy      *Lazy
+	overrides []*override
+}
+
+// override - represents repository replacing singleton until restored.
+type override struct {
+	repo Repository
+}
+
+// Registry - represents goroutine safe registry of lazily constructed singletons looked up through Repository.
+// Instances can be overridden for a scope (e.g. test) and reset so next lookup constructs them again.
+type Registry struct {
+	mu      sync.Mutex
+	backoff Backoff
+	entries map[string]*registryEntry
+}
+
+// NewRegistry - creates new instance of Registry.
+func NewRegistry(backoff Backoff) *Registry {
+	return &Registry{backoff: backoff, entries: make(map[string]*registryEntry)}
+}
+
+// Register - registers singleton constructed by init on first lookup, replaces previous registration.
+func (r *Registry) Register(name string, init RepositoryInitializer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := &registryEntry{init: init}
+	if prev, ok := r.entries[name]; ok {
+		e.overrides = prev.overrides
+	}
+	e.lazy = r.newLazy(init)
+	r.entries[name] = e
+}
+
+// newLazy - wraps initializer into lazy singleton.
+func (r *Registry) newLazy(init RepositoryInitializer) *Lazy {
+	return NewLazy(func(ctx context.Context) (interface{}, error) { return init(ctx) }, r.backoff)
+}
+
+// Get - returns latest override of named singleton, otherwise singleton itself constructing it when needed.
+func (r *Registry) Get(ctx context.Context, name string) (Repository, error) {
+	for {
+		r.mu.Lock()
+		e, ok := r.entries[name]
+		if !ok {
+			r.mu.Unlock()
+			return nil, fmt.Errorf("%w: %q", ErrUnknownInstance, name)
+		}
+		if n := len(e.overrides); n > 0 {
+			repo := e.overrides[n-1].repo
+			r.mu.Unlock()
+			return repo, nil
+		}
+		lazy := e.lazy
+		r.mu.Unlock()
+
+		v, err := lazy.Get(ctx)
+		if errors.Is(err, ErrDiscarded) {
+			// singleton was reset meanwhile, look up its replacement
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return v.(Repository), nil
+	}
+}
+
+// Override - replaces named singleton with provided repository until returned restore is called.
+// Overrides nest, restoring one brings back whatever it replaced; restore is safe to call more than once.
+func (r *Registry) Override(name string, repo Repository) (restore func(), err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownInstance, name)
+	}
+	o := &override{repo: repo}
+	e.overrides = append(e.overrides, o)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			e := r.entries[name]
+			for i := range e.overrides {
+				if e.overrides[i] == o {
+					e.overrides = append(e.overrides[:i:i], e.overrides[i+1:]...)
+					return
+				}
+			}
+		})
+	}, nil
+}
+
+// OverrideFor - replaces named singleton with provided repository until scope ends.
+func (r *Registry) OverrideFor(scope Cleaner, name string, repo Repository) error {
+	restore, err := r.Override(name, repo)
+	if err != nil {
+		return err
+	}
+	scope.Cleanup(restore)
+	return nil
+}
+
+// Reset - drops instance and overrides of named singleton, next lookup constructs it again.
+// Dropped instance is closed if it's io.Closer, pending construction is cancelled and its result closed.
+func (r *Registry) Reset(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownInstance, name)
+	}
+	e.lazy.Discard()
+	e.lazy = r.newLazy(e.init)
+	e.overrides = nil
+	return nil
+}
+
+// ResetAll - resets every registered singleton.
+func (r *Registry) ResetAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		e.lazy.Discard()
+		e.lazy = r.newLazy(e.init)
+		e.overrides = nil
+	}
+}
+
+// Names - returns names of registered singletons in order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewInMemoryRepository - creates repository holding provided cities, useful as fake in tests.
+func NewInMemoryRepository(cities ...City) Repository {
+	return newSingletonDatabase(cities)
+}
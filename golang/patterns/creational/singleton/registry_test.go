@@ -0,0 +1,219 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closingRepository - represents repository recording whether it was closed.
+type closingRepository struct {
+	Repository
+	closed int32
+}
+
+// Close - marks repository closed (implements io.Closer).
+func (c *closingRepository) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+// isClosed - reports whether repository was closed.
+func (c *closingRepository) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+// newTestRegistry - creates registry with singleton constructed from provided cities, counting constructions.
+func newTestRegistry(constructed *int32, cities ...City) *Registry {
+	r := NewRegistry(Backoff{Initial: time.Millisecond})
+	r.Register(DatabaseName, func(ctx context.Context) (Repository, error) {
+		atomic.AddInt32(constructed, 1)
+		return NewInMemoryRepository(cities...), nil
+	})
+	return r
+}
+
+func TestDefaultRegistryOverrideFor(t *testing.T) {
+	outer := NewInMemoryRepository()
+	fake := NewInMemoryRepository(City{Name: "Alpha", Population: 1}, City{Name: "Beta", Population: 2})
+	if err := DefaultRegistry.OverrideFor(t, DatabaseName, outer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("fake injected", func(t *testing.T) {
+		if err := DefaultRegistry.OverrideFor(t, DatabaseName, fake); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		db, err := NewSingletoneDatabase()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if db != fake {
+			t.Fatalf("expected fake repository, got %T", db)
+		}
+		total, err := GetTotalPopulation(context.Background(), "Alpha", "Beta")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("expected total population 3, got %d", total)
+		}
+	})
+
+	// override is restored by t.Cleanup of the subtest
+	db, err := DefaultRegistry.Get(context.Background(), DatabaseName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db != outer {
+		t.Error("expected override to be restored after subtest")
+	}
+}
+
+func TestRegistryOverridesNest(t *testing.T) {
+	var constructed int32
+	r := newTestRegistry(&constructed, City{Name: "Real", Population: 10})
+	first := NewInMemoryRepository(City{Name: "First", Population: 1})
+	second := NewInMemoryRepository(City{Name: "Second", Population: 2})
+
+	restoreFirst, err := r.Override(DatabaseName, first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restoreSecond, err := r.Override(DatabaseName, second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		name    string
+		restore func()
+		want    int
+	}{
+		{"latest override wins", func() {}, 2},
+		{"restoring latest brings back previous", restoreSecond, 1},
+		{"restore is idempotent", restoreSecond, 1},
+		{"restoring all brings back singleton", restoreFirst, 10},
+	}
+	for _, step := range steps {
+		step.restore()
+		db, err := r.Get(context.Background(), DatabaseName)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if got := db.TotalPopulation(); got != step.want {
+			t.Errorf("%s: expected total population %d, got %d", step.name, step.want, got)
+		}
+	}
+	if got := atomic.LoadInt32(&constructed); got != 1 {
+		t.Errorf("expected singleton constructed once, got %d", got)
+	}
+}
+
+func TestRegistryOverrideUnknown(t *testing.T) {
+	r := NewRegistry(DefaultBackoff)
+	if err := r.OverrideFor(t, "unknown", NewInMemoryRepository()); !errors.Is(err, ErrUnknownInstance) {
+		t.Errorf("expected ErrUnknownInstance, got %v", err)
+	}
+	if err := r.Reset("unknown"); !errors.Is(err, ErrUnknownInstance) {
+		t.Errorf("expected ErrUnknownInstance, got %v", err)
+	}
+}
+
+func TestRegistryResetConstructsAgain(t *testing.T) {
+	var constructed int32
+	r := newTestRegistry(&constructed, City{Name: "Real", Population: 10})
+	if err := r.OverrideFor(t, DatabaseName, NewInMemoryRepository()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Get(context.Background(), DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&constructed); got != 0 {
+		t.Fatalf("expected override to skip construction, got %d constructions", got)
+	}
+
+	r.ResetAll()
+	for i := 0; i < 2; i++ {
+		db, err := r.Get(context.Background(), DatabaseName)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := db.TotalPopulation(); got != 10 {
+			t.Errorf("expected reset to drop override, got total population %d", got)
+		}
+	}
+	if err := r.Reset(DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Get(context.Background(), DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&constructed); got != 2 {
+		t.Errorf("expected construction after every reset, got %d", got)
+	}
+}
+
+func TestRegistryResetClosesInstance(t *testing.T) {
+	repo := &closingRepository{Repository: NewInMemoryRepository()}
+	r := NewRegistry(DefaultBackoff)
+	r.Register(DatabaseName, func(ctx context.Context) (Repository, error) { return repo, nil })
+	if _, err := r.Get(context.Background(), DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Reset(DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.isClosed() {
+		t.Error("expected constructed instance to be closed on reset")
+	}
+}
+
+func TestRegistryResetCancelsPendingInit(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	pending := &closingRepository{Repository: NewInMemoryRepository()}
+	var cancelled int32
+	var calls int32
+
+	r := NewRegistry(DefaultBackoff)
+	r.Register(DatabaseName, func(ctx context.Context) (Repository, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			return NewInMemoryRepository(City{Name: "Fresh", Population: 5}), nil
+		}
+		close(started)
+		<-ctx.Done()
+		atomic.StoreInt32(&cancelled, 1)
+		<-finish
+		// initializer ignoring cancellation still hands over instance which must be released
+		return pending, nil
+	})
+
+	result := make(chan Repository, 1)
+	go func() {
+		db, err := r.Get(context.Background(), DatabaseName)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		result <- db
+	}()
+
+	<-started
+	if err := r.Reset(DatabaseName); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(finish)
+
+	db := <-result
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Error("expected pending initialization to be cancelled")
+	}
+	if !pending.isClosed() {
+		t.Error("expected instance of pending initialization to be closed")
+	}
+	if db == nil || db.TotalPopulation() != 5 {
+		t.Error("expected waiting caller to receive singleton constructed after reset")
+	}
+}
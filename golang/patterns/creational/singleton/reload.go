@@ -0,0 +1,245 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// -- Hot Reloading Repository
+
+// ErrFileChanging - file changed while repository was loading it, dataset was not swapped.
+var ErrFileChanging = errors.New("file kept changing while loading")
+
+// initialLoadAttempts - times initial load is retried while file is being written.
+const initialLoadAttempts = 5
+
+// CitiesValidator - checks freshly loaded dataset before it replaces the active one.
+type CitiesValidator func(cities []City) error
+
+// MinCities - rejects datasets with less than n cities, guards against truncated files.
+func MinCities(n int) CitiesValidator {
+	return func(cities []City) error {
+		if len(cities) < n {
+			return fmt.Errorf("expected at least %d cities, got %d", n, len(cities))
+		}
+		return nil
+	}
+}
+
+// ReloadEvent - describes reload attempt.
+type ReloadEvent struct {
+	Path   string
+	At     time.Time
+	Cities int   // cities in active dataset after reload
+	Err    error // non nil - file rejected, previous dataset is kept
+}
+
+// String - formats event.
+func (e ReloadEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("reload of %s rejected, kept %d cities: %v", e.Path, e.Cities, e.Err)
+	}
+	return fmt.Sprintf("reloaded %s: %d cities", e.Path, e.Cities)
+}
+
+// fileStamp - identifies file revision.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// ReloadingRepository - represents repository which swaps in dataset reloaded from its file atomically.
+// Every query runs against single immutable dataset, so readers never see partially loaded one.
+type ReloadingRepository struct {
+	path       string
+	validators []CitiesValidator
+	current    atomic.Value // *singletonDatabase
+
+	mu        sync.Mutex // serializes reloads, guards fields below
+	stamp     fileStamp
+	listeners []func(ReloadEvent)
+	stops     []context.CancelFunc
+}
+
+// NewReloadingRepository - creates new instance of ReloadingRepository loaded from provided file.
+// File being written is retried shortly, repository is never returned without dataset.
+func NewReloadingRepository(path string, validators ...CitiesValidator) (*ReloadingRepository, error) {
+	r := &ReloadingRepository{path: path, validators: validators}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for attempt := 1; attempt <= initialLoadAttempts; attempt++ {
+		loaded, err := r.reload()
+		if err != nil {
+			return nil, err
+		}
+		if loaded {
+			return r, nil
+		}
+		time.Sleep(time.Duration(attempt) * 10 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("loading cities from %s: %w", path, ErrFileChanging)
+}
+
+// OnReload - registers listener called after every reload attempt.
+// Listeners run once reload has released the repository, so they may call any of its methods,
+// listeners of concurrent reloads may run concurrently.
+func (r *ReloadingRepository) OnReload(fn func(ReloadEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload - loads file unconditionally, previous dataset is kept if file is invalid
+// or was being written, the latter is reported as ErrFileChanging.
+func (r *ReloadingRepository) Reload() error {
+	r.mu.Lock()
+	swapped, err := r.reload()
+	if err == nil && !swapped {
+		err = fmt.Errorf("reloading %s: %w", r.path, ErrFileChanging)
+	}
+	e, listeners := r.event(err)
+	r.mu.Unlock()
+	notify(e, listeners)
+	return err
+}
+
+// Watch - polls file modification time with provided interval and reloads changed file until ctx is done.
+func (r *ReloadingRepository) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reloadIfChanged()
+		}
+	}
+}
+
+// StartWatching - watches file in background until repository is closed.
+func (r *ReloadingRepository) StartWatching(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	r.stops = append(r.stops, cancel)
+	r.mu.Unlock()
+	go r.Watch(ctx, interval)
+}
+
+// Close - stops background watchers (implements io.Closer).
+func (r *ReloadingRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stop := range r.stops {
+		stop()
+	}
+	r.stops = nil
+	return nil
+}
+
+// reloadIfChanged - reloads file when its revision differs from loaded one.
+func (r *ReloadingRepository) reloadIfChanged() {
+	stamp, err := stat(r.path)
+	r.mu.Lock()
+	if err == nil && stamp == r.stamp {
+		r.mu.Unlock()
+		return
+	}
+	changed, err := r.reload()
+	e, listeners := r.event(err)
+	r.mu.Unlock()
+	if changed || err != nil {
+		notify(e, listeners)
+	}
+}
+
+// reload - loads, validates and swaps dataset, reports whether it was swapped. Caller holds r.mu.
+func (r *ReloadingRepository) reload() (bool, error) {
+	before, err := stat(r.path)
+	if err != nil {
+		return false, fmt.Errorf("loading cities: %w", err)
+	}
+	cities, err := LoadCities(r.path)
+	if err != nil {
+		r.stamp = before // don't retry same broken revision on every poll
+		return false, err
+	}
+	after, err := stat(r.path)
+	if err != nil {
+		return false, fmt.Errorf("loading cities: %w", err)
+	}
+	if after != before {
+		// file is being written, next poll picks up complete revision
+		return false, nil
+	}
+	r.stamp = after
+	for _, validate := range r.validators {
+		if err := validate(cities); err != nil {
+			return false, fmt.Errorf("validating %s: %w", r.path, err)
+		}
+	}
+	r.current.Store(newSingletonDatabase(cities))
+	return true, nil
+}
+
+// event - describes outcome of reload and copies listeners to be notified about it. Caller holds r.mu.
+func (r *ReloadingRepository) event(err error) (ReloadEvent, []func(ReloadEvent)) {
+	e := ReloadEvent{Path: r.path, At: time.Now(), Cities: len(r.snapshot().sorted), Err: err}
+	return e, append([]func(ReloadEvent){}, r.listeners...)
+}
+
+// notify - calls listeners with outcome of reload, caller must not hold r.mu.
+func notify(e ReloadEvent, listeners []func(ReloadEvent)) {
+	for _, fn := range listeners {
+		fn(e)
+	}
+}
+
+// stat - returns current revision of file.
+func stat(path string) (fileStamp, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
+
+// snapshot - returns active dataset.
+func (r *ReloadingRepository) snapshot() *singletonDatabase {
+	return r.current.Load().(*singletonDatabase)
+}
+
+// Snapshot - returns active dataset, consecutive queries against it see consistent data.
+func (r *ReloadingRepository) Snapshot() Repository {
+	return r.snapshot()
+}
+
+// GetCityPopulation - retrieves city population by provided name (implements Repository).
+func (r *ReloadingRepository) GetCityPopulation(name string) (int, error) {
+	return r.snapshot().GetCityPopulation(name)
+}
+
+// TopCities - retrieves n most populated cities (implements Repository).
+func (r *ReloadingRepository) TopCities(n int) []City {
+	return r.snapshot().TopCities(n)
+}
+
+// CitiesInRange - retrieves cities with population within [min, max] (implements Repository).
+func (r *ReloadingRepository) CitiesInRange(min, max int) []City {
+	return r.snapshot().CitiesInRange(min, max)
+}
+
+// CitiesWithPrefix - retrieves cities which names start with provided prefix (implements Repository).
+func (r *ReloadingRepository) CitiesWithPrefix(prefix string) []City {
+	return r.snapshot().CitiesWithPrefix(prefix)
+}
+
+// TotalPopulation - sums population of every city (implements Repository).
+func (r *ReloadingRepository) TotalPopulation() int {
+	return r.snapshot().TotalPopulation()
+}
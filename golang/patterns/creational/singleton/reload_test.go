@@ -0,0 +1,123 @@
+package singleton
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestReloadingRepository - creates repository loaded from file with provided content in temporary directory.
+func newTestReloadingRepository(t *testing.T, content string) (*ReloadingRepository, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	writeCities(t, path, content)
+	r, err := NewReloadingRepository(path, MinCities(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return r, path
+}
+
+// writeCities - replaces content of cities file.
+func writeCities(t *testing.T, path, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// reloadWithin - reloads within limited time, so reload waiting for repository fails the test instead of hanging it.
+func reloadWithin(t *testing.T, r *ReloadingRepository) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- r.Reload() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("reload did not finish")
+		return nil
+	}
+}
+
+func TestReloadingRepositoryEvents(t *testing.T) {
+	r, path := newTestReloadingRepository(t, "name,population\nTokyo,1\nLondon,2\n")
+	var events []ReloadEvent
+	r.OnReload(func(e ReloadEvent) { events = append(events, e) })
+
+	tests := []struct {
+		name    string
+		content string
+		err     bool
+		total   int
+	}{
+		{"updated", "name,population\nTokyo,1\nLondon,2\nParis,3\n", false, 6},
+		{"fails validation", "name,population\nTokyo,10\n", true, 6},
+		{"fails parsing", "name,population\nTokyo,oops\nLondon,2\n", true, 6},
+		{"fixed", "name,population\nTokyo,10\nLondon,20\n", false, 30},
+	}
+	for i, tt := range tests {
+		writeCities(t, path, tt.content)
+		err := r.Reload()
+		if (err != nil) != tt.err {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+		}
+		if got := r.TotalPopulation(); got != tt.total {
+			t.Errorf("%s: expected total %d, got %d", tt.name, tt.total, got)
+		}
+		if len(events) != i+1 || events[i].Err != err || events[i].Cities != len(r.TopCities(10)) {
+			t.Errorf("%s: expected event describing reload, got %v", tt.name, events)
+		}
+	}
+}
+
+func TestReloadingRepositoryListenerCallsRepository(t *testing.T) {
+	r, _ := newTestReloadingRepository(t, "name,population\nTokyo,1\nLondon,2\n")
+	var calls, nested int32
+	r.OnReload(func(e ReloadEvent) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			return
+		}
+		// listener reloads again, registers another listener and closes repository
+		r.OnReload(func(e ReloadEvent) { atomic.AddInt32(&nested, 1) })
+		if err := r.Reload(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		_ = r.Close()
+	})
+	if err := reloadWithin(t, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected listener to be called for both reloads, got %d calls", got)
+	}
+	if got := atomic.LoadInt32(&nested); got != 1 {
+		t.Errorf("expected listener registered by listener to be called for nested reload, got %d calls", got)
+	}
+}
+
+func TestReloadingRepositoryBlockingListener(t *testing.T) {
+	r, path := newTestReloadingRepository(t, "name,population\nTokyo,1\nLondon,2\n")
+	entered, release := make(chan struct{}), make(chan struct{})
+	var calls int32
+	r.OnReload(func(e ReloadEvent) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(entered)
+			<-release
+		}
+	})
+	defer close(release)
+
+	go func() { _ = r.Reload() }()
+	<-entered
+	// first listener call is still blocked, later reload is not stalled by it
+	writeCities(t, path, "name,population\nTokyo,10\nLondon,20\n")
+	if err := reloadWithin(t, r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := r.TotalPopulation(); got != 30 {
+		t.Errorf("expected total 30, got %d", got)
+	}
+}
@@ -1,8 +1,15 @@
 package singleton
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Singleton: restricts object creation for a type to only one instance.
@@ -16,58 +23,349 @@ func Singleton() {
 	fmt.Println("\nSingleton")
 
 	// singleton
-	sdb := NewSingletoneDatabase() // constructed
-	// sdb2 := NewSingletoneDatabase() // instantiated
-	// sdb3 := NewSingletoneDatabase() // instantiated
+	sdb, err := NewSingletoneDatabase() // constructed
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	// sdb2, _ := NewSingletoneDatabase() // instantiated
+	// sdb3, _ := NewSingletoneDatabase() // instantiated
 	const _city = "Tokyo"
-	fmt.Println(sdb.GetCityPopulation(_city))
+	population, err := sdb.GetCityPopulation(_city)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Println(population)
 
-	//
+	// queries
+	fmt.Printf("top 3: %v\n", sdb.TopCities(3))
+	fmt.Printf("10M-13M: %v\n", sdb.CitiesInRange(10_000_000, 13_000_000))
+	fmt.Printf("prefix \"T\": %v\n", sdb.CitiesWithPrefix("T"))
+	fmt.Printf("total: %d\n", sdb.TotalPopulation())
+	if _, err := sdb.GetCityPopulation("Atlantis"); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
+	// failure aware lazy singleton, failed initialization is retried after backoff
+	attempts := 0
+	flaky := NewLazy(func(ctx context.Context) (interface{}, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return newSingletonDatabase([]City{{Name: "Tokyo", Population: 13929286}}), nil
+	}, Backoff{Initial: 20 * time.Millisecond})
+	if _, err := flaky.Get(context.Background()); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", errors.Unwrap(err)))
+	}
+	var initErr *InitError
+	if _, err := flaky.Get(context.Background()); errors.As(err, &initErr) {
+		fmt.Printf("still backing off after %d attempt(s)\n", initErr.Attempts)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := flaky.Get(context.Background()); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("lazy singleton ready after %d attempts\n", attempts)
+
+	// waiting for initialization honors context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := NewLazy(func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-time.After(time.Second):
+			return struct{}{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, DefaultBackoff)
+	if _, err := slow.Get(ctx); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
+	// multiton, one database per region
+	for _, region := range []string{"europe", "asia", "europe"} {
+		rdb, err := NewRegionalDatabase(context.Background(), region)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+		fmt.Printf("%s top 2: %v\n", region, rdb.TopCities(2))
+	}
+	if _, err := NewRegionalDatabase(context.Background(), "atlantis"); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", errors.Unwrap(err)))
+	}
+	fmt.Printf("regions loaded: %v\n", regionalDatabases.Keys())
+
+	// hot reloading, dataset is swapped atomically when file changes
+	if err := hotReload(); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+
+	// registry, singleton replaced with in memory fake for a scope (e.g. test)
+	restore, err := DefaultRegistry.Override(DatabaseName, NewInMemoryRepository(
+		City{Name: "Alpha", Population: 1},
+		City{Name: "Beta", Population: 2},
+	))
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	total, err := GetTotalPopulation(context.Background(), "Alpha", "Beta")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("fake total: %d\n", total)
+	restore()
+	total, err = GetTotalPopulation(context.Background(), "Tokyo", "London")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("restored total: %d\n", total)
+
+	// reset, next lookup constructs singleton again
+	if err := DefaultRegistry.Reset(DatabaseName); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Println("database reset")
+	if _, err := NewSingletoneDatabase(); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+}
+
+// hotReload - demonstrates reloading repository watching its file.
+func hotReload() error {
+	dir, err := ioutil.TempDir("", "cities")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cities.csv")
+	if err := ioutil.WriteFile(path, []byte("name,population\nTokyo,13929286\nLondon,8908081\n"), 0o644); err != nil {
+		return err
+	}
+
+	r, err := NewReloadingRepository(path, MinCities(2))
+	if err != nil {
+		return err
+	}
+	events := make(chan ReloadEvent, 1)
+	r.OnReload(func(e ReloadEvent) { events <- e })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 5*time.Millisecond)
+
+	for _, content := range []string{
+		"name,population\nTokyo,14047594\nLondon,8982000\nParis,2165423\n", // updated
+		"name,population\nTokyo,14047594\n",                                // fails validation
+		"name,population\nTokyo,oops\nLondon,8982000\n",                    // fails parsing
+	} {
+		// write next to the file and rename, so watcher never reads half written file
+		if err := ioutil.WriteFile(path+".tmp", []byte(content), 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+		select {
+		case e := <-events:
+			fmt.Println(strings.Replace(e.String(), dir, "$TMP", -1))
+		case <-time.After(time.Second):
+			return errors.New("no reload event")
+		}
+		population, _ := r.GetCityPopulation("Tokyo")
+		fmt.Printf("Tokyo: %d, total: %d\n", population, r.TotalPopulation())
+	}
+	return nil
 }
 
 // -- Singleton
 
+var ErrCityNotFound = errors.New("city not found")
+
+// City - represents city and its population.
+type City struct {
+	Name       string `json:"name"`
+	Population int    `json:"population"`
+}
+
+// String - formats city.
+func (c City) String() string {
+	return fmt.Sprintf("%s (%d)", c.Name, c.Population)
+}
+
 // Repository - represents repository interface.
 type Repository interface {
-	GetCityPopulation(name string) string
+	GetCityPopulation(name string) (int, error)
+	TopCities(n int) []City
+	CitiesInRange(min, max int) []City
+	CitiesWithPrefix(prefix string) []City
+	TotalPopulation() int
 }
 
+// DataPath - file cities are loaded from on first access, CSV or JSON by extension.
+var DataPath = "./patterns/creational/singleton/files/cities.csv"
+
+// WatchInterval - how often singleton database checks DataPath for changes, 0 - never.
+var WatchInterval = 5 * time.Second
+
+// RegionsDir - directory regional databases are loaded from, one "<region>.csv" file per region.
+var RegionsDir = "./patterns/creational/singleton/files/regions"
+
+// DatabaseName - name singleton database is registered under in DefaultRegistry.
+const DatabaseName = "cities"
+
 // func init(){} - thread safety only
-// sync.Once - thread safety and lazyness
-var once sync.Once
-var instance *singletonDatabase
+// sync.Once - thread safety and lazyness, but failed construction can never be retried
+// Lazy - thread safety, lazyness and retry with backoff after failure
+// Registry - lazy singletons which can be overridden or reset, e.g. in tests
+var DefaultRegistry = newDefaultRegistry()
+
+// newDefaultRegistry - creates registry with singleton database registered.
+func newDefaultRegistry() *Registry {
+	r := NewRegistry(DefaultBackoff)
+	r.Register(DatabaseName, newDatabase)
+	return r
+}
+
+// newDatabase - constructs singleton database, reloaded whenever DataPath changes.
+func newDatabase(ctx context.Context) (Repository, error) {
+	fmt.Println("constructing object")
+	r, err := NewReloadingRepository(DataPath)
+	if err != nil {
+		return nil, err
+	}
+	if WatchInterval > 0 {
+		r.StartWatching(WatchInterval)
+	}
+	return r, nil
+}
+
+// regionalDatabases - multiton holding one database per region.
+var regionalDatabases = NewMultiton(func(ctx context.Context, region string) (interface{}, error) {
+	cities, err := LoadCities(filepath.Join(RegionsDir, region+".csv"))
+	if err != nil {
+		return nil, err
+	}
+	return newSingletonDatabase(cities), nil
+}, DefaultBackoff)
 
 // singletonDatabase - represents database connection, implements repository interface.
 type singletonDatabase struct {
-	DB map[string]string
-}
-
-// NewSingletoneDatabase - creates new instance of singletoneDatabase
-func NewSingletoneDatabase() *singletonDatabase {
-	// once insures that object constructed only once
-	once.Do(func() {
-		fmt.Println("constructing object")
-		instance = &singletonDatabase{
-			// mock loading data from elsewhere
-			DB: map[string]string{
-				"Beijing":  "21,542,000",
-				"Tokyo":    "13,929,286",
-				"Kinshasa": "12,691,000",
-				"Moscow":   "12,506,468",
-				"Jakarta":  "10,075,310",
-				"Seoul":    "9,838,892",
-				"Cairo":    "9,848,576",
-				"London":   "8,908,081",
-				"Tehran":   "8,693,706",
-				"Baghdad":  "6,719,500",
-			},
+	DB     map[string]int
+	sorted []City // by population descending
+}
+
+// NewSingletoneDatabase - creates new instance of singletoneDatabase, reloaded whenever DataPath changes
+func NewSingletoneDatabase() (Repository, error) {
+	return NewSingletoneDatabaseContext(context.Background())
+}
+
+// NewSingletoneDatabaseContext - creates new instance of singletoneDatabase, waiting no longer than ctx allows.
+func NewSingletoneDatabaseContext(ctx context.Context) (Repository, error) {
+	// registry insures that object constructed only once, failed construction is retried by later calls
+	// every next time just return reference to singleton, or its override
+	return DefaultRegistry.Get(ctx, DatabaseName)
+}
+
+// GetTotalPopulation - sums population of provided cities, looked up in singleton database.
+func GetTotalPopulation(ctx context.Context, names ...string) (int, error) {
+	db, err := NewSingletoneDatabaseContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, name := range names {
+		population, err := db.GetCityPopulation(name)
+		if err != nil {
+			return 0, err
+		}
+		total += population
+	}
+	return total, nil
+}
+
+// NewRegionalDatabase - returns database of provided region, constructed once per region.
+func NewRegionalDatabase(ctx context.Context, region string) (Repository, error) {
+	v, err := regionalDatabases.Get(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*singletonDatabase), nil
+}
+
+// newSingletonDatabase - creates database indexed from provided cities.
+func newSingletonDatabase(cities []City) *singletonDatabase {
+	sdb := &singletonDatabase{DB: make(map[string]int, len(cities))}
+	for _, c := range cities {
+		sdb.DB[c.Name] = c.Population
+	}
+	for name, population := range sdb.DB {
+		sdb.sorted = append(sdb.sorted, City{Name: name, Population: population})
+	}
+	sort.Slice(sdb.sorted, func(i, j int) bool {
+		if sdb.sorted[i].Population != sdb.sorted[j].Population {
+			return sdb.sorted[i].Population > sdb.sorted[j].Population
 		}
+		return sdb.sorted[i].Name < sdb.sorted[j].Name
 	})
-	// every next time just return reference to singleton
-	return instance
+	return sdb
+}
+
+// GetCityPopulation - retrieves city population by provided name.
+func (sdb *singletonDatabase) GetCityPopulation(name string) (int, error) {
+	population, ok := sdb.DB[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrCityNotFound, name)
+	}
+	return population, nil
 }
 
-// GetCityByPopulation - retrieves city population by provided name.
-func (sdb *singletonDatabase) GetCityPopulation(name string) string {
-	return sdb.DB[name]
+// TopCities - retrieves n most populated cities.
+func (sdb *singletonDatabase) TopCities(n int) []City {
+	if n > len(sdb.sorted) {
+		n = len(sdb.sorted)
+	}
+	if n < 0 {
+		n = 0
+	}
+	return append([]City{}, sdb.sorted[:n]...)
+}
+
+// CitiesInRange - retrieves cities with population within [min, max], most populated first.
+func (sdb *singletonDatabase) CitiesInRange(min, max int) []City {
+	return sdb.filter(func(c City) bool { return c.Population >= min && c.Population <= max })
+}
+
+// CitiesWithPrefix - retrieves cities which names start with provided prefix (case insensitive).
+func (sdb *singletonDatabase) CitiesWithPrefix(prefix string) []City {
+	prefix = strings.ToLower(prefix)
+	return sdb.filter(func(c City) bool { return strings.HasPrefix(strings.ToLower(c.Name), prefix) })
+}
+
+// TotalPopulation - sums population of every city.
+func (sdb *singletonDatabase) TotalPopulation() int {
+	total := 0
+	for _, c := range sdb.sorted {
+		total += c.Population
+	}
+	return total
+}
+
+// filter - retrieves cities matching predicate, most populated first.
+func (sdb *singletonDatabase) filter(match func(c City) bool) []City {
+	cities := make([]City, 0)
+	for _, c := range sdb.sorted {
+		if match(c) {
+			cities = append(cities, c)
+		}
+	}
+	return cities
 }
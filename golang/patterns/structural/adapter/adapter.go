@@ -1,10 +1,13 @@
 package adapter
 
 import (
-	"crypto/md5"
-	"encoding/json"
 	"fmt"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Adapter: allows types with incompatible interfaces to work together by wrapping its own interface around that of an already existing type.
@@ -39,15 +42,112 @@ func Adapter() {
 	vm.AddLine(NewLine(10, 0, 10, 10))
 
 	// init cache
-	pc := NewPointsCache()
+	pc := NewPointsCache(WithMaxEntries(64))
 
 	// init adapter
 	via := NewVectorToRasterAdapter(vm, pc)
+	fmt.Println(pc.Stats()) // lines converted to points
 	_ = NewVectorToRasterAdapter(vm, pc)
 	_ = NewVectorToRasterAdapter(vm, pc)
+	fmt.Println(pc.Stats()) // points got from cache
 
 	// use adaptee by client
 	pr.PrintImage(via)
+
+	// lines of any direction and other shapes
+	sm := NewVectorImage()
+	sm.AddLine(NewLine(10, 0, 0, 0))
+	sm.AddLine(NewLine(0, 0, 6, 10))
+	sm.AddShape(NewPolygon(true, NewPoint(14, 0), NewPoint(22, 0), NewPoint(18, 8)))
+	sm.AddShape(NewPolyline(NewPoint(24, 0), NewPoint(27, 10), NewPoint(30, 0)))
+	sm.AddShape(NewCircle(37, 5, 5))
+	sm.AddShape(NewEllipse(52, 5, 8, 4))
+	pr.PrintImage(NewVectorToRasterAdapter(sm, pc))
+
+	// adapting many images concurrently through shared cache
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			im := NewVectorImage()
+			im.AddLine(NewLine(0, 0, 10, 0))
+			im.AddLine(NewLine(0, 0, i, 10))
+			_ = NewVectorToRasterAdapter(im, pc)
+		}(i)
+	}
+	wg.Wait()
+	fmt.Println(pc.Stats())
+
+	// bounded cache evicts least recently used lines
+	small := NewPointsCache(WithMaxEntries(2))
+	_ = NewVectorToRasterAdapter(vm, small)
+	fmt.Println(small.Stats())
+
+	// export adapted image into files
+	dir, err := ioutil.TempDir("", "adapter")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"image.pbm", "image.pgm", "image.png", "image.svg"} {
+		path := filepath.Join(dir, name)
+		err := pr.ExportFile(path, via, WithScale(4), WithColors(color.RGBA{R: 0x1f, G: 0x6f, B: 0xeb, A: 0xff}, color.White))
+		if err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+		fmt.Printf("exported %s: %d bytes\n", name, fi.Size())
+	}
+	if err := pr.Export(ioutil.Discard, via, "gif"); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+
+	// import drawing from svg file
+	drawing, report, err := ImportSVGFile("./patterns/structural/adapter/files/house.svg")
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Println(report)
+	house := NewVectorToRasterAdapter(drawing, pc)
+	pr.PrintImage(house)
+
+	// reverse adapter traces raster points back into lines
+	fmt.Printf("traced square: %v\n", NewRasterToVectorAdapter(via, 0).Image)
+	traced := NewRasterToVectorAdapter(house, 0)
+	fmt.Printf("traced house: %d lines from %d points\n", len(traced.Image), len(house.GetPoints()))
+
+	// transforms compose as adapters over any printable image, negative coordinates are fine
+	pr.PrintImage(NewTransformedImage(via, Translate(-5, -5)))
+	pr.PrintImage(NewTransformedImage(via, Rotate(45).Around(NewPoint(5, 5))))
+	pr.PrintImage(NewTransformedImage(rm, Scale(2, 2).Then(FlipVertical())))
+
+	// viewport prints only a window of large image
+	pr.PrintImage(NewViewport(house, NewRect(40, 0, 59, 23)))
+
+	// terminal renderers pack several pixels into single character
+	for _, mode := range []RenderMode{RenderHalfBlock, RenderBraille} {
+		if err := pr.Render(os.Stdout, house, WithRenderMode(mode)); err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+	}
+	big := NewVectorImage()
+	big.AddShape(NewCircle(60, 60, 58))
+	big.AddShape(NewPolygon(false, NewPoint(60, 2), NewPoint(110, 89), NewPoint(10, 89)))
+	err = pr.Render(os.Stdout, NewVectorToRasterAdapter(big, nil),
+		WithRenderMode(RenderBraille), WithFitWidth(30), WithANSIColors(color.RGBA{R: 0x1f, G: 0x6f, B: 0xeb, A: 0xff}, nil))
+	if err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 }
 
 // -- Adapter
@@ -58,7 +158,7 @@ type vectorToRasterAdapter struct {
 	cache PointsCacheInterface
 }
 
-// NewVectorToRasterAdapter - creates new instance of vector to raster image adapter.
+// NewVectorToRasterAdapter - creates new instance of vector to raster image adapter, lc may be nil.
 func NewVectorToRasterAdapter(vi *VectorImage, lc PointsCacheInterface) PrintableImage { // return interface
 	va := &vectorToRasterAdapter{
 		cache: lc,
@@ -67,48 +167,32 @@ func NewVectorToRasterAdapter(vi *VectorImage, lc PointsCacheInterface) Printabl
 	for _, line := range vi.Image {
 		va.addLine(line)
 	}
+	// convert remaining vector shapes into a raster image points
+	for _, shape := range vi.Shapes {
+		va.image = append(va.image, shape.Rasterize()...)
+	}
 	return va
 }
 
 // addLine - adds vector image line in a raster image point representation.
 func (va *vectorToRasterAdapter) addLine(l Line) {
-
-	// try to get points from cache
-	sum := va.cache.GetSum(l)
-	if va.cache.Found(sum) {
-		fmt.Println("got points from cache")
-		va.image = va.cache.Retrieve(sum)
+	if va.cache == nil {
+		va.image = append(va.image, l.Rasterize()...)
 		return
 	}
 
-	fmt.Println("converting lines to points")
-	// if both x and y grows, then line is diagonal
-	if l.X1 != l.X2 && l.Y1 != l.Y2 {
-		for i, j := l.X1, l.Y1; i < l.X2 && j < l.Y2; i, j = i+1, j+1 {
-			va.image = append(va.image, Point{X: i, Y: j})
-		}
-
-		for i, j := l.X2, l.Y2; i > l.X1 && j > l.Y1; i, j = i-1, j-1 {
-			va.image = append(va.image, Point{X: i, Y: j})
-		}
-	}
-
-	// if y doesn't change, then line is - horizontal
-	if l.Y1 == l.Y2 {
-		for i := l.X1; i <= l.X2; i++ {
-			va.image = append(va.image, Point{X: i, Y: l.Y1})
-		}
+	// try to get points from cache
+	if pp, ok := va.cache.Retrieve(l); ok {
+		va.image = append(va.image, pp...)
+		return
 	}
 
-	// if x doesen't change, then line is - vertical
-	if l.X1 == l.X2 {
-		for i := l.Y1; i <= l.Y2; i++ {
-			va.image = append(va.image, Point{X: l.X1, Y: i})
-		}
-	}
+	// line of any direction and slope
+	pp := l.Rasterize()
+	va.image = append(va.image, pp...)
 
-	// store points to cache
-	va.cache.Store(sum, va.image)
+	// store points of this line only to cache
+	va.cache.Store(l, pp)
 }
 
 // GetPoints - gets raster image pixel points (implement PrintableImage).
@@ -118,9 +202,10 @@ func (va *vectorToRasterAdapter) GetPoints() []Point {
 
 // -- Adaptee
 
-// VectorImage - represents image built of []Line.
+// VectorImage - represents image built of []Line and other []Shape.
 type VectorImage struct {
-	Image []Line
+	Image  []Line
+	Shapes []Shape
 }
 
 // NewVectorImage - creates instance of new VectorImage.
@@ -133,6 +218,11 @@ func (rm *VectorImage) AddLine(l Line) {
 	rm.Image = append(rm.Image, l)
 }
 
+// AddShape - add shape (polyline, polygon, circle, ellipse) to the image.
+func (rm *VectorImage) AddShape(s Shape) {
+	rm.Shapes = append(rm.Shapes, s)
+}
+
 // -- Target
 
 // RasterImage - represents image build of []Point.
@@ -173,22 +263,14 @@ func NewImagePrinter() *ImagePrinter {
 // PrintImage - prints image into the console.
 func (ip *ImagePrinter) PrintImage(rm PrintableImage) {
 
-	// get max height and max width of the image
-	maxH := 0 // y
-	maxW := 0 // x
-	for _, p := range rm.GetPoints() {
-		if p.Y > maxH {
-			maxH = p.Y
-		}
-		if p.X > maxW {
-			maxW = p.X
-		}
-	}
+	// get bounding box of the image, origin is always included and coordinates may be negative
+	b, _ := Bounds(rm.GetPoints())
+	b = b.Union(Rect{})
 
-	// create a matrix based on max height and max width values
-	mx := make([][]string, maxH+1)
+	// create a matrix based on height and width of bounding box
+	mx := make([][]string, b.Height())
 	for y := range mx {
-		mx[y] = make([]string, maxW+1)
+		mx[y] = make([]string, b.Width())
 		// fill matrix with whitespaces
 		for x := range mx[y] {
 			mx[y][x] = " "
@@ -197,8 +279,8 @@ func (ip *ImagePrinter) PrintImage(rm PrintableImage) {
 
 	// range over each point in raster image
 	for _, p := range rm.GetPoints() {
-		// represent point on matrix
-		mx[p.Y][p.X] = "."
+		// represent point on matrix, relative to bottom left corner of bounding box
+		mx[p.Y-b.Min.Y][p.X-b.Min.X] = "."
 	}
 
 	// combine represented points into a string representation
@@ -210,48 +292,6 @@ func (ip *ImagePrinter) PrintImage(rm PrintableImage) {
 	fmt.Println(result)
 }
 
-// -- Cache
-
-// PointsCacheInterface - represents points cache interface.
-type PointsCacheInterface interface {
-	GetSum(l interface{}) [16]byte
-	Found(sum [16]byte) bool
-	Retrieve(sum [16]byte) []Point
-	Store(sum [16]byte, pp []Point)
-}
-
-// PointsCache - represets points cache.
-type PointsCache struct {
-	Cache map[[16]byte][]Point
-}
-
-// NewPointsCache - creates new instance of PointsCache.
-func NewPointsCache() *PointsCache {
-	return &PointsCache{Cache: make(map[[16]byte][]Point)}
-}
-
-// GetSum - generates md5 sum from provided line.
-func (lc *PointsCache) GetSum(l interface{}) [16]byte {
-	bs, _ := json.Marshal(l)
-	return md5.Sum(bs)
-}
-
-// Found - indicates if key was found in map.
-func (lc *PointsCache) Found(sum [16]byte) bool {
-	_, ok := lc.Cache[sum]
-	return ok
-}
-
-// Retrieve - retrieves []Point from cache from by provided sum.
-func (lc *PointsCache) Retrieve(sum [16]byte) []Point {
-	return lc.Cache[sum]
-}
-
-// Store - stores []point in cache by provided sum.
-func (lc *PointsCache) Store(sum [16]byte, pp []Point) {
-	lc.Cache[sum] = pp
-}
-
 // -- Auxiliary types
 
 // Line - represents line.
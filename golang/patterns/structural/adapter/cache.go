@@ -0,0 +1,213 @@
+package adapter
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// -- Cache
+
+// PointsCacheInterface - represents goroutine safe cache of rasterized lines.
+type PointsCacheInterface interface {
+	Retrieve(l Line) ([]Point, bool)
+	Store(l Line, pp []Point)
+}
+
+// CacheStats - represents cache usage statistics.
+type CacheStats struct {
+	Hits, Misses, Evictions uint64
+	Entries                 int
+	Bytes                   int64
+}
+
+// String - formats statistics.
+func (s CacheStats) String() string {
+	return fmt.Sprintf("hits: %d, misses: %d, evictions: %d, entries: %d, bytes: %d",
+		s.Hits, s.Misses, s.Evictions, s.Entries, s.Bytes)
+}
+
+// CacheOption - represents PointsCache configuration option.
+type CacheOption func(c *cacheConfig)
+
+// cacheConfig - represents PointsCache configuration.
+type cacheConfig struct {
+	maxEntries int
+	maxBytes   int64
+	shards     int
+}
+
+// WithMaxEntries - bounds number of cached lines, 0 - unbounded.
+func WithMaxEntries(n int) CacheOption {
+	return func(c *cacheConfig) { c.maxEntries = n }
+}
+
+// WithMaxBytes - bounds approximate memory held by cached points, 0 - unbounded.
+func WithMaxBytes(n int64) CacheOption {
+	return func(c *cacheConfig) { c.maxBytes = n }
+}
+
+// WithShards - splits cache into n independently locked shards to reduce contention.
+func WithShards(n int) CacheOption {
+	return func(c *cacheConfig) { c.shards = n }
+}
+
+// minShardEntries - least number of entries each shard of bounded cache holds.
+const minShardEntries = 8
+
+// minShardBytes - least memory each shard of bounded cache holds, enough for a few long lines.
+const minShardBytes = 4 << 10
+
+// PointsCache - represets goroutine safe LRU cache of rasterized lines.
+// Bounds are split evenly between shards, so each shard evicts its own least recently used lines.
+type PointsCache struct {
+	shards []*cacheShard
+
+	hits, misses, evictions uint64 // accessed atomically
+}
+
+// cacheShard - represents independently locked part of cache.
+type cacheShard struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	order      *list.List // front - most recently used
+	entries    map[Line]*list.Element
+}
+
+// cacheEntry - represents cached line.
+type cacheEntry struct {
+	line   Line
+	points []Point
+	size   int64
+}
+
+// NewPointsCache - creates new instance of PointsCache, unbounded unless options say otherwise.
+func NewPointsCache(opts ...CacheOption) *PointsCache {
+	cfg := cacheConfig{shards: 16}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	// small bounds split between many shards would evict far from least recently used order
+	if cfg.maxEntries > 0 && cfg.shards > cfg.maxEntries/minShardEntries {
+		cfg.shards = cfg.maxEntries / minShardEntries
+	}
+	if cfg.maxBytes > 0 && int64(cfg.shards) > cfg.maxBytes/minShardBytes {
+		cfg.shards = int(cfg.maxBytes / minShardBytes)
+	}
+	if cfg.shards < 1 {
+		cfg.shards = 1
+	}
+
+	pc := &PointsCache{shards: make([]*cacheShard, cfg.shards)}
+	for i := range pc.shards {
+		pc.shards[i] = &cacheShard{
+			maxEntries: int(divideBound(int64(cfg.maxEntries), cfg.shards, i)),
+			maxBytes:   divideBound(cfg.maxBytes, cfg.shards, i),
+			order:      list.New(),
+			entries:    make(map[Line]*list.Element),
+		}
+	}
+	return pc
+}
+
+// divideBound - returns part of bound given to i-th shard, parts sum up to bound, 0 stays unbounded.
+func divideBound(bound int64, shards, i int) int64 {
+	part := bound / int64(shards)
+	if int64(i) < bound%int64(shards) {
+		part++
+	}
+	return part
+}
+
+// shard - returns shard responsible for provided line.
+func (pc *PointsCache) shard(l Line) *cacheShard {
+	return pc.shards[l.Hash()%uint64(len(pc.shards))]
+}
+
+// Retrieve - retrieves points of provided line, returned slice is shared and must not be modified.
+func (pc *PointsCache) Retrieve(l Line) ([]Point, bool) {
+	s := pc.shard(l)
+	s.mu.Lock()
+	var pp []Point
+	el, ok := s.entries[l]
+	if ok {
+		s.order.MoveToFront(el)
+		// element value is replaced by concurrent Store of the same line
+		pp = el.Value.(*cacheEntry).points
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		atomic.AddUint64(&pc.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&pc.hits, 1)
+	return pp, true
+}
+
+// Store - stores copy of line points, evicting least recently used lines when over bounds.
+func (pc *PointsCache) Store(l Line, pp []Point) {
+	e := &cacheEntry{line: l, points: append([]Point(nil), pp...), size: entrySize(pp)}
+	s := pc.shard(l)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxBytes > 0 && e.size > s.maxBytes {
+		return // would evict everything and still not fit
+	}
+	if el, ok := s.entries[l]; ok {
+		s.bytes -= el.Value.(*cacheEntry).size
+		el.Value = e
+		s.order.MoveToFront(el)
+	} else {
+		s.entries[l] = s.order.PushFront(e)
+	}
+	s.bytes += e.size
+
+	for (s.maxEntries > 0 && len(s.entries) > s.maxEntries) || (s.maxBytes > 0 && s.bytes > s.maxBytes) {
+		oldest := s.order.Back()
+		victim := s.order.Remove(oldest).(*cacheEntry)
+		delete(s.entries, victim.line)
+		s.bytes -= victim.size
+		atomic.AddUint64(&pc.evictions, 1)
+	}
+}
+
+// Stats - returns cache usage statistics.
+func (pc *PointsCache) Stats() CacheStats {
+	st := CacheStats{
+		Hits:      atomic.LoadUint64(&pc.hits),
+		Misses:    atomic.LoadUint64(&pc.misses),
+		Evictions: atomic.LoadUint64(&pc.evictions),
+	}
+	for _, s := range pc.shards {
+		s.mu.Lock()
+		st.Entries += len(s.entries)
+		st.Bytes += s.bytes
+		s.mu.Unlock()
+	}
+	return st
+}
+
+// entrySize - approximates memory held by cached points.
+func entrySize(pp []Point) int64 {
+	return int64(unsafe.Sizeof(cacheEntry{})) + int64(len(pp))*int64(unsafe.Sizeof(Point{}))
+}
+
+// Hash - mixes line coordinates into 64 bit hash (FNV-1a over coordinates, finalized so every bit depends on all of them).
+func (l Line) Hash() uint64 {
+	const offset, prime = 14695981039346656037, 1099511628211
+	h := uint64(offset)
+	for _, v := range [...]int{l.X1, l.Y1, l.X2, l.Y2} {
+		h ^= uint64(v)
+		h *= prime
+	}
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,54 @@
+package adapter
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPointsCacheByteBudget(t *testing.T) {
+	l := NewLine(0, 0, 40, 10)
+	pp := l.Rasterize()
+	size := entrySize(pp)
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		stored   bool
+	}{
+		{"budget below one entry keeps nothing", size - 1, false},
+		{"budget of one entry keeps it", size, true},
+		{"budget of few entries keeps it", 3 * size, true},
+		{"budget split between shards keeps it", 64 * minShardBytes, true},
+	}
+	for _, tt := range tests {
+		pc := NewPointsCache(WithMaxBytes(tt.maxBytes))
+		pc.Store(l, pp)
+		if _, ok := pc.Retrieve(l); ok != tt.stored {
+			t.Errorf("%s: expected stored %v, got %v", tt.name, tt.stored, ok)
+		}
+		if st := pc.Stats(); st.Bytes > tt.maxBytes {
+			t.Errorf("%s: expected at most %d bytes, got %d", tt.name, tt.maxBytes, st.Bytes)
+		}
+	}
+}
+
+func TestPointsCacheConcurrentStore(t *testing.T) {
+	pc := NewPointsCache(WithMaxEntries(16))
+	lines := []Line{NewLine(0, 0, 5, 5), NewLine(0, 0, 9, 3), NewLine(2, 7, 2, 0)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				l := lines[j%len(lines)]
+				pc.Store(l, l.Rasterize())
+				if pp, ok := pc.Retrieve(l); ok && len(pp) != len(l.Rasterize()) {
+					t.Errorf("expected %d points of %v, got %d", len(l.Rasterize()), l, len(pp))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
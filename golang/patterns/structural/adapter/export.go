@@ -0,0 +1,244 @@
+package adapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// -- Export
+
+var (
+	ErrEmptyImage        = errors.New("image has no points")
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+)
+
+// Format - represents image file format.
+type Format string
+
+const (
+	FormatPBM Format = "pbm" // Netpbm bitmap, foreground is black
+	FormatPGM Format = "pgm" // Netpbm graymap, colors converted to gray
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Origin - represents corner of exported image where y axis starts.
+type Origin int
+
+const (
+	OriginBottomLeft Origin = iota // y grows up, same as PrintImage
+	OriginTopLeft                  // y grows down, same as image coordinates
+)
+
+// ExportOption - represents export configuration option.
+type ExportOption func(c *exportConfig)
+
+// exportConfig - represents export configuration.
+type exportConfig struct {
+	scale      int
+	foreground color.Color
+	background color.Color
+	origin     Origin
+}
+
+// WithScale - renders every point as scale x scale pixels.
+func WithScale(scale int) ExportOption {
+	return func(c *exportConfig) { c.scale = scale }
+}
+
+// WithColors - sets point and background colors, transparent background is left out of SVG.
+// Nil colors keep default black points and white background.
+func WithColors(foreground, background color.Color) ExportOption {
+	if foreground == nil {
+		foreground = color.Black
+	}
+	if background == nil {
+		background = color.White
+	}
+	return func(c *exportConfig) { c.foreground, c.background = foreground, background }
+}
+
+// WithOrigin - sets corner where y axis starts.
+func WithOrigin(o Origin) ExportOption {
+	return func(c *exportConfig) { c.origin = o }
+}
+
+// newExportConfig - applies options over defaults.
+func newExportConfig(opts []ExportOption) exportConfig {
+	cfg := exportConfig{scale: 1, foreground: color.Black, background: color.White}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.scale < 1 {
+		cfg.scale = 1
+	}
+	return cfg
+}
+
+// Export - writes image in provided format.
+func (ip *ImagePrinter) Export(w io.Writer, rm PrintableImage, format Format, opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	bm, err := newBitmap(rm.GetPoints(), cfg.origin)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatPBM:
+		return writePBM(w, bm, cfg)
+	case FormatPGM:
+		return writePGM(w, bm, cfg)
+	case FormatPNG:
+		return png.Encode(w, bm.image(cfg))
+	case FormatSVG:
+		return writeSVG(w, bm, cfg)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// ExportFile - writes image to file, format is chosen by file extension.
+func (ip *ImagePrinter) ExportFile(path string, rm PrintableImage, opts ...ExportOption) error {
+	format := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	var buf bytes.Buffer
+	if err := ip.Export(&buf, rm, format, opts...); err != nil {
+		return fmt.Errorf("exporting %s: %w", path, err)
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// -- Bitmap
+
+// bitmap - represents points laid out on a grid, row 0 is the top row.
+type bitmap struct {
+	w, h int
+	set  []bool
+}
+
+// newBitmap - lays points out on grid spanning their bounding box and the (0, 0) origin, like PrintImage does.
+func newBitmap(pp []Point, origin Origin) (*bitmap, error) {
+	if len(pp) == 0 {
+		return nil, ErrEmptyImage
+	}
+	b, _ := Bounds(pp)
+	b = b.Union(Rect{})
+
+	bm := &bitmap{w: b.Width(), h: b.Height()}
+	bm.set = make([]bool, bm.w*bm.h)
+	for _, p := range pp {
+		row := b.Max.Y - p.Y
+		if origin == OriginTopLeft {
+			row = p.Y - b.Min.Y
+		}
+		bm.set[row*bm.w+p.X-b.Min.X] = true
+	}
+	return bm, nil
+}
+
+// at - reports whether point is set at provided column and row.
+func (bm *bitmap) at(col, row int) bool {
+	return bm.set[row*bm.w+col]
+}
+
+// image - renders bitmap into scaled image.
+func (bm *bitmap) image(cfg exportConfig) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, bm.w*cfg.scale, bm.h*cfg.scale))
+	fg := color.NRGBAModel.Convert(cfg.foreground)
+	bg := color.NRGBAModel.Convert(cfg.background)
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			if bm.at(x/cfg.scale, y/cfg.scale) {
+				img.Set(x, y, fg)
+			} else {
+				img.Set(x, y, bg)
+			}
+		}
+	}
+	return img
+}
+
+// writePBM - writes bitmap as binary PBM (P4), set points are black.
+func writePBM(w io.Writer, bm *bitmap, cfg exportConfig) error {
+	width, height := bm.w*cfg.scale, bm.h*cfg.scale
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "P4\n%d %d\n", width, height)
+	row := make([]byte, (width+7)/8)
+	for y := 0; y < height; y++ {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < width; x++ {
+			if bm.at(x/cfg.scale, y/cfg.scale) {
+				row[x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+		buf.Write(row)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writePGM - writes bitmap as binary PGM (P5) with 8 bit gray levels.
+func writePGM(w io.Writer, bm *bitmap, cfg exportConfig) error {
+	width, height := bm.w*cfg.scale, bm.h*cfg.scale
+	fg := color.GrayModel.Convert(cfg.foreground).(color.Gray).Y
+	bg := color.GrayModel.Convert(cfg.background).(color.Gray).Y
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "P5\n%d %d\n255\n", width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if bm.at(x/cfg.scale, y/cfg.scale) {
+				buf.WriteByte(fg)
+			} else {
+				buf.WriteByte(bg)
+			}
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeSVG - writes bitmap as SVG, horizontal runs of points are merged into single rectangle.
+func writeSVG(w io.Writer, bm *bitmap, cfg exportConfig) error {
+	s := cfg.scale
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n",
+		bm.w*s, bm.h*s, bm.w*s, bm.h*s)
+	if _, _, _, a := cfg.background.RGBA(); a > 0 {
+		fmt.Fprintf(&buf, "<rect width=\"100%%\" height=\"100%%\" %s/>\n", svgFill(cfg.background))
+	}
+	fmt.Fprintf(&buf, "<g %s>\n", svgFill(cfg.foreground))
+	for row := 0; row < bm.h; row++ {
+		for col := 0; col < bm.w; {
+			if !bm.at(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < bm.w && bm.at(col, row) {
+				col++
+			}
+			fmt.Fprintf(&buf, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n", start*s, row*s, (col-start)*s, s)
+		}
+	}
+	buf.WriteString("</g>\n</svg>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// svgFill - formats color as SVG fill attributes.
+func svgFill(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	fill := fmt.Sprintf("fill=\"#%02x%02x%02x\"", n.R, n.G, n.B)
+	if n.A < 0xff {
+		fill += fmt.Sprintf(" fill-opacity=\"%.3g\"", float64(n.A)/0xff)
+	}
+	return fill
+}
@@ -0,0 +1,157 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// corners - returns image with points in opposite corners of 3x2 grid, top right and bottom left when printed.
+func corners() *RasterImage {
+	return &RasterImage{Image: []Point{NewPoint(0, 0), NewPoint(2, 1)}}
+}
+
+// export - exports image in provided format, fails test on error.
+func export(t *testing.T, rm PrintableImage, format Format, opts ...ExportOption) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewImagePrinter().Export(&buf, rm, format, opts...); err != nil {
+		t.Fatalf("%s: unexpected error: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportNetpbm(t *testing.T) {
+	gray := color.Gray{Y: 0x40}
+	tests := []struct {
+		name   string
+		format Format
+		opts   []ExportOption
+		want   string
+	}{
+		{"pbm", FormatPBM, nil, "P4\n3 2\n\x20\x80"},
+		{"pbm top left origin", FormatPBM, []ExportOption{WithOrigin(OriginTopLeft)}, "P4\n3 2\n\x80\x20"},
+		{"pbm scaled", FormatPBM, []ExportOption{WithScale(2)}, "P4\n6 4\n\x0c\x0c\xc0\xc0"},
+		{"pbm row padding", FormatPBM, []ExportOption{WithScale(3)}, "P4\n9 6\n\x03\x80\x03\x80\x03\x80\xe0\x00\xe0\x00\xe0\x00"},
+		{"pgm", FormatPGM, nil, "P5\n3 2\n255\n\xff\xff\x00\x00\xff\xff"},
+		{"pgm colors", FormatPGM, []ExportOption{WithColors(color.White, gray)}, "P5\n3 2\n255\n\x40\x40\xff\xff\x40\x40"},
+		{"pgm nil colors", FormatPGM, []ExportOption{WithColors(nil, nil)}, "P5\n3 2\n255\n\xff\xff\x00\x00\xff\xff"},
+	}
+	for _, tt := range tests {
+		if got := string(export(t, corners(), tt.format, tt.opts...)); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestExportPNG(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	tests := []struct {
+		name   string
+		opts   []ExportOption
+		fg, bg color.NRGBA
+	}{
+		{"defaults", nil, color.NRGBA{A: 0xff}, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{"colors", []ExportOption{WithColors(red, color.Transparent)}, red, color.NRGBA{}},
+		{"nil colors", []ExportOption{WithColors(nil, nil)}, color.NRGBA{A: 0xff}, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+	}
+	for _, tt := range tests {
+		img, err := png.Decode(bytes.NewReader(export(t, corners(), FormatPNG, append(tt.opts, WithScale(2))...)))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if b := img.Bounds(); b.Dx() != 6 || b.Dy() != 4 {
+			t.Fatalf("%s: expected 6x4 image, got %v", tt.name, b)
+		}
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 6; x++ {
+				want := tt.bg
+				if (x >= 4 && y < 2) || (x < 2 && y >= 2) {
+					want = tt.fg
+				}
+				if got := color.NRGBAModel.Convert(img.At(x, y)); got != want {
+					t.Errorf("%s: expected %v at (%d, %d), got %v", tt.name, want, x, y, got)
+				}
+			}
+		}
+	}
+}
+
+func TestExportSVG(t *testing.T) {
+	line := &RasterImage{Image: NewLine(0, 0, 3, 0).Rasterize()}
+	tests := []struct {
+		name  string
+		image PrintableImage
+		opts  []ExportOption
+		want  string
+	}{
+		{"runs merged into rectangles", line, nil, `<svg xmlns="http://www.w3.org/2000/svg" width="4" height="1" viewBox="0 0 4 1" shape-rendering="crispEdges">
+<rect width="100%" height="100%" fill="#ffffff"/>
+<g fill="#000000">
+<rect x="0" y="0" width="4" height="1"/>
+</g>
+</svg>
+`},
+		{"scaled with transparent background", corners(), []ExportOption{WithScale(2), WithColors(color.NRGBA{B: 0xff, A: 0x80}, color.Transparent)},
+			`<svg xmlns="http://www.w3.org/2000/svg" width="6" height="4" viewBox="0 0 6 4" shape-rendering="crispEdges">
+<g fill="#0000ff" fill-opacity="0.502">
+<rect x="4" y="0" width="2" height="2"/>
+<rect x="0" y="2" width="2" height="2"/>
+</g>
+</svg>
+`},
+		{"nil colors", line, []ExportOption{WithColors(nil, nil)}, `<svg xmlns="http://www.w3.org/2000/svg" width="4" height="1" viewBox="0 0 4 1" shape-rendering="crispEdges">
+<rect width="100%" height="100%" fill="#ffffff"/>
+<g fill="#000000">
+<rect x="0" y="0" width="4" height="1"/>
+</g>
+</svg>
+`},
+	}
+	for _, tt := range tests {
+		got := export(t, tt.image, FormatSVG, tt.opts...)
+		if string(got) != tt.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tt.name, tt.want, got)
+		}
+		if err := xml.Unmarshal(got, new(struct{})); err != nil {
+			t.Errorf("%s: expected well formed document, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	ip := NewImagePrinter()
+	if err := ip.Export(ioutil.Discard, &RasterImage{}, FormatPNG); !errors.Is(err, ErrEmptyImage) {
+		t.Errorf("expected ErrEmptyImage, got %v", err)
+	}
+	if err := ip.Export(ioutil.Discard, corners(), Format("gif")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestExportFile(t *testing.T) {
+	ip := NewImagePrinter()
+	dir := t.TempDir()
+	for _, format := range []Format{FormatPBM, FormatPGM, FormatPNG, FormatSVG} {
+		path := filepath.Join(dir, "corners."+string(format))
+		if err := ip.ExportFile(path, corners()); err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if want := export(t, corners(), format); !bytes.Equal(got, want) {
+			t.Errorf("%s: expected file to hold exported image", format)
+		}
+	}
+	path := filepath.Join(dir, "corners.GIF")
+	if err := ip.ExportFile(path, corners()); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
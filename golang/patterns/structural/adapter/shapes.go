@@ -0,0 +1,263 @@
+package adapter
+
+import (
+	"math"
+	"sort"
+)
+
+// -- Shapes
+
+// Shape - represents vector primitive which can be rasterized into points.
+type Shape interface {
+	Rasterize() []Point
+}
+
+// Rasterize - converts line in any direction into points using Bresenham's algorithm (implements Shape).
+// Both directions of the line produce the same points, starting from (X1, Y1).
+func (l Line) Rasterize() []Point {
+	if l.Y2 < l.Y1 || (l.Y2 == l.Y1 && l.X2 < l.X1) {
+		// ties are rounded the same way whichever end line is drawn from
+		pp := NewLine(l.X2, l.Y2, l.X1, l.Y1).Rasterize()
+		for i, j := 0, len(pp)-1; i < j; i, j = i+1, j-1 {
+			pp[i], pp[j] = pp[j], pp[i]
+		}
+		return pp
+	}
+
+	dx, sx := abs(l.X2-l.X1), sign(l.X2-l.X1)
+	dy, sy := -abs(l.Y2-l.Y1), sign(l.Y2-l.Y1)
+	pp := make([]Point, 0, max(dx, -dy)+1)
+
+	x, y, e := l.X1, l.Y1, dx+dy
+	for {
+		pp = append(pp, Point{X: x, Y: y})
+		if x == l.X2 && y == l.Y2 {
+			return pp
+		}
+		// step along the axis (or both) whose accumulated error is smaller
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y += sy
+		}
+	}
+}
+
+// Polyline - represents connected line segments.
+type Polyline struct {
+	Points []Point
+}
+
+// NewPolyline - creates new instance of Polyline.
+func NewPolyline(pp ...Point) Polyline {
+	return Polyline{Points: pp}
+}
+
+// Rasterize - converts every segment into points (implements Shape).
+func (pl Polyline) Rasterize() []Point {
+	return rasterizePath(pl.Points, false)
+}
+
+// Polygon - represents closed polyline, optionally filled.
+type Polygon struct {
+	Points []Point
+	Filled bool
+}
+
+// NewPolygon - creates new instance of Polygon.
+func NewPolygon(filled bool, pp ...Point) Polygon {
+	return Polygon{Points: pp, Filled: filled}
+}
+
+// Rasterize - converts outline and, when filled, interior into points (implements Shape).
+func (pg Polygon) Rasterize() []Point {
+	pp := rasterizePath(pg.Points, true)
+	if !pg.Filled || len(pg.Points) < 3 {
+		return pp
+	}
+
+	// scanline fill: every row crosses edges an even number of times, fill between pairs of crossings
+	minY, maxY := pg.Points[0].Y, pg.Points[0].Y
+	for _, p := range pg.Points {
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	outline := make(map[Point]bool, len(pp))
+	for _, p := range pp {
+		outline[p] = true
+	}
+	for y := minY; y <= maxY; y++ {
+		var xs []float64
+		for i, a := range pg.Points {
+			b := pg.Points[(i+1)%len(pg.Points)]
+			// half open rule counts shared vertices once and skips horizontal edges
+			if (a.Y <= y && b.Y > y) || (b.Y <= y && a.Y > y) {
+				xs = append(xs, float64(a.X)+float64(y-a.Y)*float64(b.X-a.X)/float64(b.Y-a.Y))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			for x := int(math.Ceil(xs[i])); x <= int(math.Floor(xs[i+1])); x++ {
+				if p := (Point{X: x, Y: y}); !outline[p] {
+					pp = append(pp, p)
+				}
+			}
+		}
+	}
+	return pp
+}
+
+// Circle - represents circle outline.
+type Circle struct {
+	Center Point
+	R      int
+}
+
+// NewCircle - creates new instance of Circle.
+func NewCircle(x, y, r int) Circle {
+	return Circle{Center: Point{X: x, Y: y}, R: r}
+}
+
+// Rasterize - converts circle into points (implements Shape).
+func (c Circle) Rasterize() []Point {
+	return rasterizeEllipse(c.Center, c.R, c.R)
+}
+
+// Ellipse - represents axis aligned ellipse outline.
+type Ellipse struct {
+	Center Point
+	RX, RY int
+}
+
+// NewEllipse - creates new instance of Ellipse.
+func NewEllipse(x, y, rx, ry int) Ellipse {
+	return Ellipse{Center: Point{X: x, Y: y}, RX: rx, RY: ry}
+}
+
+// Rasterize - converts ellipse into points (implements Shape).
+func (e Ellipse) Rasterize() []Point {
+	return rasterizeEllipse(e.Center, e.RX, e.RY)
+}
+
+// -- Rasterization helpers
+
+// rasterizePath - converts consecutive points into connected segments, closed path returns to the first point.
+func rasterizePath(vertices []Point, closed bool) []Point {
+	if len(vertices) == 0 {
+		return nil
+	}
+	if closed && len(vertices) > 1 {
+		vertices = append(append([]Point{}, vertices...), vertices[0])
+	}
+	pp := []Point{vertices[0]}
+	for i := 1; i < len(vertices); i++ {
+		a, b := vertices[i-1], vertices[i]
+		// skip first point of the segment, it's the last point of the previous one
+		pp = append(pp, NewLine(a.X, a.Y, b.X, b.Y).Rasterize()[1:]...)
+	}
+	if closed && len(pp) > 1 {
+		pp = pp[:len(pp)-1]
+	}
+	return pp
+}
+
+// rasterizeEllipse - converts ellipse into points using midpoint ellipse algorithm.
+func rasterizeEllipse(c Point, rx, ry int) []Point {
+	rx, ry = abs(rx), abs(ry)
+	if rx == 0 || ry == 0 {
+		return NewLine(c.X-rx, c.Y-ry, c.X+rx, c.Y+ry).Rasterize()
+	}
+
+	seen := make(map[Point]bool)
+	var pp []Point
+	lastX := 0 // furthest x plotted on the major axis
+	plot := func(x, y int) {
+		if y == 0 && x > lastX {
+			lastX = x
+		}
+		for _, p := range []Point{{c.X + x, c.Y + y}, {c.X - x, c.Y + y}, {c.X + x, c.Y - y}, {c.X - x, c.Y - y}} {
+			if !seen[p] {
+				seen[p] = true
+				pp = append(pp, p)
+			}
+		}
+	}
+
+	rx2, ry2 := float64(rx*rx), float64(ry*ry)
+	x, y := 0, ry
+	dx, dy := 0.0, 2*rx2*float64(y)
+
+	// region 1: slope is shallower than -1, x steps every iteration
+	d := ry2 - rx2*float64(ry) + rx2/4
+	for dx < dy {
+		plot(x, y)
+		x++
+		dx += 2 * ry2
+		if d < 0 {
+			d += dx + ry2
+		} else {
+			y--
+			dy -= 2 * rx2
+			d += dx - dy + ry2
+		}
+	}
+
+	// region 2: slope is steeper than -1, y steps every iteration
+	d = ry2*(float64(x)+0.5)*(float64(x)+0.5) + rx2*float64(y-1)*float64(y-1) - rx2*ry2
+	for y >= 0 {
+		plot(x, y)
+		y--
+		dy -= 2 * rx2
+		if d > 0 {
+			d += rx2 - dy
+		} else {
+			x++
+			dx += 2 * ry2
+			d += dx - dy + rx2
+		}
+	}
+
+	// very flat ellipses reach the major axis before x reaches rx
+	for x := lastX + 1; x <= rx; x++ {
+		plot(x, 0)
+	}
+	return pp
+}
+
+// abs - returns absolute value.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// sign - returns -1, 0 or 1 depending on sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// min - returns smaller of two numbers.
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// max - returns larger of two numbers.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
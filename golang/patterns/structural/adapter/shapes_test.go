@@ -0,0 +1,213 @@
+package adapter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// neighbours - reports whether points are distinct and touch by side or corner.
+func neighbours(a, b Point) bool {
+	return a != b && abs(a.X-b.X) <= 1 && abs(a.Y-b.Y) <= 1
+}
+
+// pointSet - returns points as set.
+func pointSet(pp []Point) map[Point]bool {
+	set := make(map[Point]bool, len(pp))
+	for _, p := range pp {
+		set[p] = true
+	}
+	return set
+}
+
+// fourWay, eightWay - offsets to neighbours touching by side, and by side or corner.
+var (
+	fourWay  = []Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	eightWay = append([]Point{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}, fourWay...)
+)
+
+// reach - returns points reachable from start through neighbours accepted by ok.
+func reach(start Point, ok func(p Point) bool, offsets []Point) map[Point]bool {
+	seen := map[Point]bool{start: true}
+	queue := []Point{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, o := range offsets {
+			q := NewPoint(p.X+o.X, p.Y+o.Y)
+			if ok(q) && !seen[q] {
+				seen[q] = true
+				queue = append(queue, q)
+			}
+		}
+	}
+	return seen
+}
+
+func TestLineRasterizeEndpoints(t *testing.T) {
+	f := func(x1, y1, x2, y2 int8) bool {
+		pp := NewLine(int(x1), int(y1), int(x2), int(y2)).Rasterize()
+		return len(pp) > 0 &&
+			pp[0] == NewPoint(int(x1), int(y1)) &&
+			pp[len(pp)-1] == NewPoint(int(x2), int(y2))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLineRasterizeConnected(t *testing.T) {
+	f := func(x1, y1, x2, y2 int8) bool {
+		pp := NewLine(int(x1), int(y1), int(x2), int(y2)).Rasterize()
+		for i := 1; i < len(pp); i++ {
+			if !neighbours(pp[i-1], pp[i]) {
+				return false
+			}
+		}
+		// no more points than steps along the major axis
+		return len(pp) == max(abs(int(x2)-int(x1)), abs(int(y2)-int(y1)))+1
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLineRasterizeSymmetric(t *testing.T) {
+	f := func(x1, y1, x2, y2 int8) bool {
+		forward := NewLine(int(x1), int(y1), int(x2), int(y2)).Rasterize()
+		backward := NewLine(int(x2), int(y2), int(x1), int(y1)).Rasterize()
+		if len(forward) != len(backward) {
+			return false
+		}
+		for i := range forward {
+			if forward[i] != backward[len(backward)-1-i] {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEllipseRasterizeClosed(t *testing.T) {
+	f := func(x, y int8, a, b uint8) bool {
+		// radii of at least 1, degenerate ellipses are lines
+		c, rx, ry := NewPoint(int(x), int(y)), int(a%64)+1, int(b%64)+1
+		pp := rasterizeEllipse(c, rx, ry)
+		set := pointSet(pp)
+		if len(set) != len(pp) {
+			return false
+		}
+
+		// outline is one piece
+		if len(reach(pp[0], func(p Point) bool { return set[p] }, eightWay)) != len(set) {
+			return false
+		}
+
+		// and closed, filling from the center through side neighbours never gets past the outline
+		inside := reach(c, func(p Point) bool {
+			return !set[p] && abs(p.X-c.X) <= rx && abs(p.Y-c.Y) <= ry
+		}, fourWay)
+		for p := range inside {
+			if abs(p.X-c.X) == rx || abs(p.Y-c.Y) == ry {
+				return false
+			}
+		}
+		return !set[c]
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// randomPath - represents vertices of few random segments, generated for property tests.
+type randomPath []Point
+
+// Generate - generates 1 to 6 vertices within 24x24 area (implements quick.Generator).
+func (randomPath) Generate(r *rand.Rand, size int) reflect.Value {
+	pp := make(randomPath, 1+r.Intn(6))
+	for i := range pp {
+		pp[i] = NewPoint(r.Intn(24)-12, r.Intn(24)-12)
+	}
+	return reflect.ValueOf(pp)
+}
+
+// connected - reports whether every point touches the next one, and last touches first when closed.
+func connected(pp []Point, closed bool) bool {
+	for i := 1; i < len(pp); i++ {
+		if !neighbours(pp[i-1], pp[i]) {
+			return false
+		}
+	}
+	return !closed || len(pp) < 2 || neighbours(pp[len(pp)-1], pp[0])
+}
+
+func TestPolylineRasterizeEndpoints(t *testing.T) {
+	f := func(vertices randomPath) bool {
+		pp := NewPolyline(vertices...).Rasterize()
+		return len(pp) > 0 && pp[0] == vertices[0] && pp[len(pp)-1] == vertices[len(vertices)-1]
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPolylineRasterizeConnected(t *testing.T) {
+	f := func(vertices randomPath) bool {
+		pp := NewPolyline(vertices...).Rasterize()
+		// every vertex is visited, in order
+		next := 0
+		for _, p := range pp {
+			for next < len(vertices) && p == vertices[next] {
+				next++
+			}
+		}
+		return connected(pp, false) && next == len(vertices)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPolygonRasterizeClosed(t *testing.T) {
+	f := func(vertices randomPath) bool {
+		pp := NewPolygon(false, vertices...).Rasterize()
+		set := pointSet(pp)
+		for _, v := range vertices {
+			if !set[v] {
+				return false
+			}
+		}
+		return len(pp) > 0 && pp[0] == vertices[0] && connected(pp, true)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPolygonRasterizeFill(t *testing.T) {
+	f := func(vertices randomPath) bool {
+		outline := NewPolygon(false, vertices...).Rasterize()
+		filled := NewPolygon(true, vertices...).Rasterize()
+		if len(filled) < len(outline) || !reflect.DeepEqual(filled[:len(outline)], outline) {
+			return false // filled polygon starts with its outline
+		}
+
+		bounds, _ := Bounds(vertices)
+		seen := pointSet(outline)
+		for _, p := range filled[len(outline):] {
+			// interior stays within vertices and never repeats itself or the outline
+			if !bounds.Contains(p) || seen[p] {
+				return false
+			}
+			seen[p] = true
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,576 @@
+package adapter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// -- SVG Import
+
+var ErrInvalidSVG = errors.New("invalid svg")
+
+// SVGReport - describes parts of SVG document which were skipped during import.
+type SVGReport struct {
+	Unsupported []string // element paths, e.g. "svg/g/circle", or "svg/path: command C"
+}
+
+// String - formats report.
+func (r SVGReport) String() string {
+	if len(r.Unsupported) == 0 {
+		return "all elements imported"
+	}
+	return "unsupported: " + strings.Join(r.Unsupported, ", ")
+}
+
+// ImportSVGFile - loads vector image from SVG file.
+func ImportSVGFile(path string) (*VectorImage, SVGReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, SVGReport{}, err
+	}
+	defer f.Close()
+	return ImportSVG(f)
+}
+
+// ImportSVG - loads vector image from SVG document.
+//
+// Supported are <line>, <polyline>, <polygon>, <rect> and <path> with M/L/H/V/Z commands (either case),
+// nested in <g> elements with transform attributes. Closed shapes are filled unless fill is "none".
+// SVG y axis grows down, so image is flipped to keep it upright when printed.
+func ImportSVG(r io.Reader) (*VectorImage, SVGReport, error) {
+	var report SVGReport
+	im := &svgImporter{report: &report}
+	if err := im.decode(xml.NewDecoder(r)); err != nil {
+		return nil, report, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+	}
+	return im.image(), report, nil
+}
+
+// svgState - represents attributes inherited by nested elements.
+type svgState struct {
+	name      string // element path
+	transform affine
+	fill      bool
+}
+
+// svgShape - represents imported shape in SVG coordinates.
+type svgShape struct {
+	points []svgPoint
+	closed bool
+	filled bool
+}
+
+// svgPoint - represents point in SVG coordinates.
+type svgPoint struct {
+	X, Y float64
+}
+
+// svgImporter - represents SVG document being imported.
+type svgImporter struct {
+	report *SVGReport
+	shapes []svgShape
+	height float64 // viewport height used to flip y axis, 0 - unknown
+}
+
+// decode - walks through document collecting shapes.
+func (im *svgImporter) decode(dec *xml.Decoder) error {
+	stack := []svgState{{transform: identity(), fill: true}}
+	skip := 0 // depth inside skipped subtree
+	seenRoot := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 {
+				skip++
+				continue
+			}
+			parent := stack[len(stack)-1]
+			st, err := im.start(t, parent, !seenRoot)
+			if err != nil {
+				return fmt.Errorf("<%s>: %w", t.Name.Local, err)
+			}
+			seenRoot = true
+			if st == nil {
+				skip = 1
+				continue
+			}
+			stack = append(stack, *st)
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if !seenRoot {
+		return errors.New("no root element")
+	}
+	return nil
+}
+
+// start - imports element, returns state for its children or nil when its subtree is skipped.
+func (im *svgImporter) start(el xml.StartElement, parent svgState, root bool) (*svgState, error) {
+	attrs := make(map[string]string, len(el.Attr))
+	for _, a := range el.Attr {
+		attrs[a.Name.Local] = a.Value
+	}
+	st := svgState{name: el.Name.Local, transform: parent.transform, fill: parent.fill}
+	if parent.name != "" {
+		st.name = parent.name + "/" + el.Name.Local
+	}
+	if root {
+		if el.Name.Local != "svg" {
+			return nil, errors.New("root element must be svg")
+		}
+		return &st, im.viewport(attrs, &st)
+	}
+
+	if v, ok := attrs["transform"]; ok {
+		t, err := parseTransform(v)
+		if err != nil {
+			return nil, err
+		}
+		st.transform = st.transform.multiply(t)
+	}
+	if fill, ok := fillOf(attrs); ok {
+		st.fill = fill != "none"
+	}
+
+	var err error
+	switch el.Name.Local {
+	case "g":
+		return &st, nil
+	case "line":
+		err = im.line(attrs, st)
+	case "polyline", "polygon":
+		err = im.poly(attrs, st, el.Name.Local == "polygon")
+	case "rect":
+		err = im.rect(attrs, st)
+	case "path":
+		err = im.path(attrs, st)
+	case "title", "desc", "metadata":
+		return nil, nil // not drawn
+	default:
+		im.report.Unsupported = append(im.report.Unsupported, st.name)
+		return nil, nil
+	}
+	return &st, err
+}
+
+// viewport - maps viewBox onto image origin and remembers height used to flip y axis.
+func (im *svgImporter) viewport(attrs map[string]string, st *svgState) error {
+	if v, ok := attrs["viewBox"]; ok {
+		nn, err := parseNumbers(v)
+		if err != nil || len(nn) != 4 {
+			return fmt.Errorf("invalid viewBox %q", v)
+		}
+		st.transform = translate(-nn[0], -nn[1])
+		im.height = nn[3]
+		return nil
+	}
+	if v, ok := attrs["height"]; ok {
+		h, err := parseLength(v)
+		if err != nil {
+			return err
+		}
+		im.height = h
+	}
+	return nil
+}
+
+// add - stores shape transformed into image coordinates.
+func (im *svgImporter) add(st svgState, pp []svgPoint, closed bool) {
+	if len(pp) == 0 {
+		return
+	}
+	for i, p := range pp {
+		pp[i].X, pp[i].Y = st.transform.apply(p.X, p.Y)
+	}
+	im.shapes = append(im.shapes, svgShape{points: pp, closed: closed, filled: closed && st.fill})
+}
+
+// line - imports <line>.
+func (im *svgImporter) line(attrs map[string]string, st svgState) error {
+	nn, err := lengths(attrs, "x1", "y1", "x2", "y2")
+	if err != nil {
+		return err
+	}
+	im.add(st, []svgPoint{{nn[0], nn[1]}, {nn[2], nn[3]}}, false)
+	return nil
+}
+
+// poly - imports <polyline> and <polygon>.
+func (im *svgImporter) poly(attrs map[string]string, st svgState, closed bool) error {
+	nn, err := parseNumbers(attrs["points"])
+	if err != nil {
+		return fmt.Errorf("invalid points: %w", err)
+	}
+	if len(nn)%2 != 0 {
+		return fmt.Errorf("odd number of coordinates in points %q", attrs["points"])
+	}
+	pp := make([]svgPoint, 0, len(nn)/2)
+	for i := 0; i < len(nn); i += 2 {
+		pp = append(pp, svgPoint{nn[i], nn[i+1]})
+	}
+	im.add(st, pp, closed)
+	return nil
+}
+
+// rect - imports <rect>, rounded corners are drawn square.
+func (im *svgImporter) rect(attrs map[string]string, st svgState) error {
+	nn, err := lengths(attrs, "x", "y", "width", "height")
+	if err != nil {
+		return err
+	}
+	x, y, w, h := nn[0], nn[1], nn[2], nn[3]
+	if w <= 0 || h <= 0 {
+		return nil // not rendered per specification
+	}
+	im.add(st, []svgPoint{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, true)
+	return nil
+}
+
+// path - imports <path>, every subpath becomes separate shape.
+func (im *svgImporter) path(attrs map[string]string, st svgState) error {
+	tokens, err := tokenizePath(attrs["d"])
+	if err != nil {
+		return err
+	}
+
+	var cur, start svgPoint
+	var sub []svgPoint
+	flush := func(closed bool) {
+		if len(sub) > 1 {
+			im.add(st, sub, closed)
+		}
+		sub = nil
+	}
+
+	if len(tokens) > 0 && tokens[0].cmd != 'M' && tokens[0].cmd != 'm' {
+		return errors.New("path must start with moveto command")
+	}
+	cmd := byte(0)
+	for i := 0; i < len(tokens); {
+		if tokens[i].cmd != 0 {
+			cmd = tokens[i].cmd
+			i++
+		} else if cmd == 0 {
+			return errors.New("number must follow a command")
+		}
+		relative := cmd >= 'a' && cmd <= 'z'
+		arg := func() (float64, error) {
+			if i >= len(tokens) || tokens[i].cmd != 0 {
+				return 0, fmt.Errorf("missing argument of command %c", cmd)
+			}
+			i++
+			return tokens[i-1].num, nil
+		}
+
+		switch cmd {
+		case 'M', 'm', 'L', 'l':
+			x, err := arg()
+			if err != nil {
+				return err
+			}
+			y, err := arg()
+			if err != nil {
+				return err
+			}
+			if relative {
+				x, y = cur.X+x, cur.Y+y
+			}
+			cur = svgPoint{x, y}
+			if cmd == 'M' || cmd == 'm' {
+				flush(false)
+				start = cur
+				// following coordinate pairs are implicit lineto
+				cmd = map[byte]byte{'M': 'L', 'm': 'l'}[cmd]
+			} else if len(sub) == 0 {
+				sub = append(sub, start) // drawing continues after Z
+			}
+			sub = append(sub, cur)
+		case 'H', 'h', 'V', 'v':
+			n, err := arg()
+			if err != nil {
+				return err
+			}
+			switch cmd {
+			case 'H':
+				cur.X = n
+			case 'h':
+				cur.X += n
+			case 'V':
+				cur.Y = n
+			case 'v':
+				cur.Y += n
+			}
+			if len(sub) == 0 {
+				sub = append(sub, start)
+			}
+			sub = append(sub, cur)
+		case 'Z', 'z':
+			flush(true)
+			cur = start
+			cmd = 0 // Z takes no arguments, next token must be a command
+			if i < len(tokens) && tokens[i].cmd == 0 {
+				return errors.New("unexpected number after Z")
+			}
+		default:
+			im.report.Unsupported = append(im.report.Unsupported, fmt.Sprintf("%s: command %c", st.name, cmd))
+			flush(false)
+			return nil
+		}
+	}
+	flush(false)
+	return nil
+}
+
+// image - converts collected shapes into vector image with y axis pointing up.
+func (im *svgImporter) image() *VectorImage {
+	height := im.height
+	if height == 0 {
+		for _, s := range im.shapes {
+			for _, p := range s.points {
+				height = math.Max(height, p.Y)
+			}
+		}
+	}
+
+	vi := NewVectorImage()
+	for _, s := range im.shapes {
+		pp := make([]Point, len(s.points))
+		for i, p := range s.points {
+			pp[i] = Point{X: int(math.Round(p.X)), Y: int(math.Round(height - p.Y))}
+		}
+		switch {
+		case len(pp) == 2 && !s.closed:
+			vi.AddLine(NewLine(pp[0].X, pp[0].Y, pp[1].X, pp[1].Y))
+		case s.closed:
+			vi.AddShape(NewPolygon(s.filled, pp...))
+		default:
+			vi.AddShape(NewPolyline(pp...))
+		}
+	}
+	return vi
+}
+
+// -- SVG attribute parsing
+
+// fillOf - returns fill from style property or attribute, style takes precedence.
+func fillOf(attrs map[string]string) (string, bool) {
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		if kv := strings.SplitN(decl, ":", 2); len(kv) == 2 && strings.TrimSpace(kv[0]) == "fill" {
+			return strings.TrimSpace(kv[1]), true
+		}
+	}
+	fill, ok := attrs["fill"]
+	return strings.TrimSpace(fill), ok
+}
+
+// lengths - parses provided length attributes, missing ones are 0.
+func lengths(attrs map[string]string, names ...string) ([]float64, error) {
+	nn := make([]float64, len(names))
+	for i, name := range names {
+		v, ok := attrs[name]
+		if !ok {
+			continue
+		}
+		n, err := parseLength(v)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		nn[i] = n
+	}
+	return nn, nil
+}
+
+// parseLength - parses length in user units, "px" suffix is allowed.
+func parseLength(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "px")
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported length %q", s)
+	}
+	return n, nil
+}
+
+// pathToken - represents command letter or number of path data.
+type pathToken struct {
+	cmd byte
+	num float64
+}
+
+// tokenizePath - splits path data into commands and numbers.
+func tokenizePath(d string) ([]pathToken, error) {
+	var tokens []pathToken
+	for i := 0; i < len(d); {
+		c := d[i]
+		switch {
+		case c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			if c != 'e' && c != 'E' {
+				tokens = append(tokens, pathToken{cmd: c})
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected %q in path data", c)
+		default:
+			n, size, err := scanNumber(d[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, pathToken{num: n})
+			i += size
+		}
+	}
+	return tokens, nil
+}
+
+// parseNumbers - parses list of numbers separated by whitespace and/or commas.
+func parseNumbers(s string) ([]float64, error) {
+	tokens, err := tokenizePath(s)
+	if err != nil {
+		return nil, err
+	}
+	nn := make([]float64, 0, len(tokens))
+	for _, t := range tokens {
+		if t.cmd != 0 {
+			return nil, fmt.Errorf("unexpected %q in number list", t.cmd)
+		}
+		nn = append(nn, t.num)
+	}
+	return nn, nil
+}
+
+// scanNumber - scans number at the beginning of s, numbers may follow each other without separator ("1-2", "0.5.5").
+func scanNumber(s string) (float64, int, error) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits, dot := 0, false
+	for ; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits++
+		} else if s[i] == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+	}
+	if digits == 0 {
+		return 0, 0, fmt.Errorf("invalid number at %q", s)
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number %q", s[:i])
+	}
+	return n, i, nil
+}
+
+// -- SVG transforms
+
+// parseTransform - parses SVG transform list, e.g. "translate(10 20) rotate(45)".
+func parseTransform(s string) (affine, error) {
+	m := identity()
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		open := strings.IndexByte(rest, '(')
+		end := strings.IndexByte(rest, ')')
+		if open < 0 || end < open {
+			return m, fmt.Errorf("invalid transform %q", s)
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseNumbers(rest[open+1 : end])
+		if err != nil {
+			return m, fmt.Errorf("invalid transform %q: %w", s, err)
+		}
+		t, err := transformOf(name, args)
+		if err != nil {
+			return m, fmt.Errorf("invalid transform %q: %w", s, err)
+		}
+		m = m.multiply(t)
+		rest = strings.TrimLeft(rest[end+1:], " ,\t\n\r")
+	}
+	return m, nil
+}
+
+// transformOf - builds transform from its SVG name and arguments.
+func transformOf(name string, args []float64) (affine, error) {
+	argc := func(counts ...int) error {
+		for _, n := range counts {
+			if len(args) == n {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s takes %v arguments, got %d", name, counts, len(args))
+	}
+	switch name {
+	case "matrix":
+		if err := argc(6); err != nil {
+			return affine{}, err
+		}
+		return affine{args[0], args[1], args[2], args[3], args[4], args[5]}, nil
+	case "translate":
+		if err := argc(1, 2); err != nil {
+			return affine{}, err
+		}
+		args = append(args, 0)
+		return translate(args[0], args[1]), nil
+	case "scale":
+		if err := argc(1, 2); err != nil {
+			return affine{}, err
+		}
+		args = append(args, args[0])
+		return affine{a: args[0], d: args[1]}, nil
+	case "rotate":
+		if err := argc(1, 3); err != nil {
+			return affine{}, err
+		}
+		sin, cos := math.Sincos(args[0] * math.Pi / 180)
+		r := affine{a: cos, b: sin, c: -sin, d: cos}
+		if len(args) == 3 {
+			r = translate(args[1], args[2]).multiply(r).multiply(translate(-args[1], -args[2]))
+		}
+		return r, nil
+	case "skewX":
+		if err := argc(1); err != nil {
+			return affine{}, err
+		}
+		return affine{a: 1, c: math.Tan(args[0] * math.Pi / 180), d: 1}, nil
+	case "skewY":
+		if err := argc(1); err != nil {
+			return affine{}, err
+		}
+		return affine{a: 1, b: math.Tan(args[0] * math.Pi / 180), d: 1}, nil
+	default:
+		return affine{}, fmt.Errorf("unknown transform %q", name)
+	}
+}
@@ -0,0 +1,163 @@
+package adapter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// svgDoc - wraps elements into SVG document 100 units high, so imported y is 100 - y.
+func svgDoc(elements string) string {
+	return `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100">` + elements + `</svg>`
+}
+
+// importSVG - imports document, fails test on error.
+func importSVG(t *testing.T, name, doc string) (*VectorImage, SVGReport) {
+	t.Helper()
+	vi, report, err := ImportSVG(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", name, err)
+	}
+	return vi, report
+}
+
+func TestImportSVGTransforms(t *testing.T) {
+	const diagonal = `<line x1="0" y1="0" x2="10" y2="10"/>`
+	tests := []struct {
+		name string
+		doc  string
+		want Line
+	}{
+		{"none", svgDoc(diagonal), NewLine(0, 100, 10, 90)},
+		{"translate", svgDoc(`<g transform="translate(5 20)">` + diagonal + `</g>`), NewLine(5, 80, 15, 70)},
+		{"translate x only", svgDoc(`<g transform="translate(5)">` + diagonal + `</g>`), NewLine(5, 100, 15, 90)},
+		{"scale", svgDoc(`<g transform="scale(2)">` + diagonal + `</g>`), NewLine(0, 100, 20, 80)},
+		{"scale axes", svgDoc(`<g transform="scale(2, 3)">` + diagonal + `</g>`), NewLine(0, 100, 20, 70)},
+		{"rotate", svgDoc(`<g transform="rotate(90)">` + diagonal + `</g>`), NewLine(0, 100, -10, 90)},
+		{"rotate around point", svgDoc(`<g transform="rotate(90 10 10)">` + diagonal + `</g>`), NewLine(20, 100, 10, 90)},
+		{"matrix", svgDoc(`<g transform="matrix(1 0 0 1 3 4)">` + diagonal + `</g>`), NewLine(3, 96, 13, 86)},
+		{"skewX", svgDoc(`<g transform="skewX(45)">` + diagonal + `</g>`), NewLine(0, 100, 20, 90)},
+		{"skewY", svgDoc(`<g transform="skewY(45)">` + diagonal + `</g>`), NewLine(0, 100, 10, 80)},
+		{"list applies right to left", svgDoc(`<g transform="translate(10 0), scale(2)">` + diagonal + `</g>`), NewLine(10, 100, 30, 80)},
+		{"nested groups", svgDoc(`<g transform="translate(10 0)"><g transform="scale(2)">` + diagonal + `</g></g>`), NewLine(10, 100, 30, 80)},
+		{"element transform", svgDoc(`<g transform="translate(10 0)"><line x1="0" y1="0" x2="10" y2="10" transform="scale(2)"/></g>`), NewLine(10, 100, 30, 80)},
+		{"viewBox", `<svg viewBox="5 5 20 20"><line x1="5" y1="5" x2="15" y2="15"/></svg>`, NewLine(0, 20, 10, 10)},
+	}
+	for _, tt := range tests {
+		vi, _ := importSVG(t, tt.name, tt.doc)
+		if want := vectorOf(tt.want); !reflect.DeepEqual(vi, want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, want, vi)
+		}
+	}
+}
+
+func TestImportSVGPath(t *testing.T) {
+	corner := []Point{NewPoint(0, 100), NewPoint(10, 100), NewPoint(10, 90)}
+	tests := []struct {
+		name string
+		d    string
+		want *VectorImage
+	}{
+		{"absolute", "M 0 0 L 10 0 L 10 10", vectorOf(NewPolyline(corner...))},
+		{"relative", "m 1 1 l 9 -1 l 0 10", vectorOf(NewPolyline(NewPoint(1, 99), NewPoint(10, 100), NewPoint(10, 90)))},
+		{"implicit lineto", "M0 0 10 0 10 10", vectorOf(NewPolyline(corner...))},
+		{"implicit relative lineto", "m0 0 10 0 0 10", vectorOf(NewPolyline(corner...))},
+		{"horizontal and vertical", "M0 0 H10 V10 h-5 v-5", vectorOf(NewPolyline(append(corner, NewPoint(5, 90), NewPoint(5, 95))...))},
+		{"close", "M0 0 h10 v10 Z", vectorOf(NewPolygon(false, corner...))},
+		{"drawing after close starts at subpath start", "M0 0 h10 v10 z l 0 10", vectorOf(NewPolygon(false, corner...), NewLine(0, 100, 0, 90))},
+		{"subpaths", "M0 0 h10 M0 5 h10", vectorOf(NewLine(0, 100, 10, 100), NewLine(0, 95, 10, 95))},
+		{"compact numbers", "M0,0L10-5", vectorOf(NewLine(0, 100, 10, 105))},
+		{"exponent", "M0 0L1e1 0", vectorOf(NewLine(0, 100, 10, 100))},
+		{"single point", "M5 5", NewVectorImage()},
+	}
+	for _, tt := range tests {
+		vi, report := importSVG(t, tt.name, svgDoc(`<path fill="none" d="`+tt.d+`"/>`))
+		if !reflect.DeepEqual(vi, tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, vi)
+		}
+		if len(report.Unsupported) > 0 {
+			t.Errorf("%s: expected nothing unsupported, got %v", tt.name, report)
+		}
+	}
+}
+
+func TestImportSVGElements(t *testing.T) {
+	square := []Point{NewPoint(0, 100), NewPoint(10, 100), NewPoint(10, 90), NewPoint(0, 90)}
+	tests := []struct {
+		name     string
+		elements string
+		want     *VectorImage
+	}{
+		{"rect", `<rect x="0" y="0" width="10px" height="10"/>`, vectorOf(NewPolygon(true, square...))},
+		{"empty rect", `<rect width="0" height="10"/>`, NewVectorImage()},
+		{"polygon", `<polygon points="0,0 10,0 10,10 0,10"/>`, vectorOf(NewPolygon(true, square...))},
+		{"polyline", `<polyline points="0,0 10,0 10,10 0,10"/>`, vectorOf(NewPolyline(square...))},
+		{"fill none", `<rect width="10" height="10" fill="none"/>`, vectorOf(NewPolygon(false, square...))},
+		{"style over attribute", `<rect width="10" height="10" fill="black" style="stroke: red; fill: none"/>`, vectorOf(NewPolygon(false, square...))},
+		{"inherited fill", `<g fill="none"><rect width="10" height="10"/></g>`, vectorOf(NewPolygon(false, square...))},
+		{"overridden fill", `<g fill="none"><rect width="10" height="10" fill="red"/></g>`, vectorOf(NewPolygon(true, square...))},
+	}
+	for _, tt := range tests {
+		if vi, _ := importSVG(t, tt.name, svgDoc(tt.elements)); !reflect.DeepEqual(vi, tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, vi)
+		}
+	}
+}
+
+func TestImportSVGReport(t *testing.T) {
+	vi, report := importSVG(t, "report", svgDoc(`
+		<title>skipped</title><desc>skipped</desc>
+		<g><circle cx="5" cy="5" r="3"/><line x1="0" y1="0" x2="10" y2="0"/></g>
+		<text x="0" y="0"><tspan>nested elements are not reported</tspan></text>
+		<path fill="none" d="M 0 10 L 5 10 C 6 10, 7 10, 8 10 L 10 10"/>`))
+
+	want := []string{"svg/g/circle", "svg/text", "svg/path: command C"}
+	if !reflect.DeepEqual(report.Unsupported, want) {
+		t.Errorf("expected unsupported %v, got %v", want, report.Unsupported)
+	}
+	if got := report.String(); got != "unsupported: svg/g/circle, svg/text, svg/path: command C" {
+		t.Errorf("expected report to list unsupported elements, got %q", got)
+	}
+	// supported elements and path up to unsupported command are still imported
+	if wantImage := vectorOf(NewLine(0, 100, 10, 100), NewLine(0, 90, 5, 90)); !reflect.DeepEqual(vi, wantImage) {
+		t.Errorf("expected %+v, got %+v", wantImage, vi)
+	}
+
+	if _, report := importSVG(t, "complete", svgDoc(`<line x1="0" y1="0" x2="10" y2="0"/>`)); report.String() != "all elements imported" {
+		t.Errorf("expected nothing unsupported, got %q", report)
+	}
+	_, report, err := ImportSVGFile("files/house.svg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"svg/circle", "svg/path: command C"}; !reflect.DeepEqual(report.Unsupported, want) {
+		t.Errorf("house: expected unsupported %v, got %v", want, report.Unsupported)
+	}
+}
+
+func TestImportSVGInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"empty document", ""},
+		{"malformed xml", `<svg><line></svg>`},
+		{"root not svg", `<g><line x1="0" y1="0" x2="1" y2="1"/></g>`},
+		{"invalid viewBox", `<svg viewBox="0 0 10"></svg>`},
+		{"invalid length", svgDoc(`<line x1="1em"/>`)},
+		{"odd points", svgDoc(`<polygon points="0,0 10"/>`)},
+		{"unknown transform", svgDoc(`<g transform="shear(2)"/>`)},
+		{"transform arguments", svgDoc(`<g transform="rotate(1 2)"/>`)},
+		{"unclosed transform", svgDoc(`<g transform="scale(2"/>`)},
+		{"path without moveto", svgDoc(`<path d="L 0 0"/>`)},
+		{"path missing argument", svgDoc(`<path d="M 0"/>`)},
+		{"path number after close", svgDoc(`<path d="M 0 0 h 1 Z 5"/>`)},
+		{"path invalid number", svgDoc(`<path d="M 0 0 L . 1"/>`)},
+	}
+	for _, tt := range tests {
+		if _, _, err := ImportSVG(strings.NewReader(tt.doc)); !errors.Is(err, ErrInvalidSVG) {
+			t.Errorf("%s: expected ErrInvalidSVG, got %v", tt.name, err)
+		}
+	}
+}
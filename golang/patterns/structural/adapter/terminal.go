@@ -0,0 +1,172 @@
+package adapter
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// -- Terminal Rendering
+
+// RenderMode - represents how pixels are packed into terminal characters.
+type RenderMode int
+
+const (
+	RenderDots      RenderMode = iota // one pixel per character, same as PrintImage
+	RenderHalfBlock                   // 1x2 pixels per character using ▀ ▄ █
+	RenderBraille                     // 2x4 pixels per character using braille patterns
+)
+
+// cellSize - returns pixels covered by single character.
+func (m RenderMode) cellSize() (w, h int) {
+	switch m {
+	case RenderHalfBlock:
+		return 1, 2
+	case RenderBraille:
+		return 2, 4
+	default:
+		return 1, 1
+	}
+}
+
+// RenderOption - represents terminal rendering option.
+type RenderOption func(c *renderConfig)
+
+// renderConfig - represents terminal rendering configuration.
+type renderConfig struct {
+	mode       RenderMode
+	foreground color.Color
+	background color.Color
+	width      int
+}
+
+// WithRenderMode - sets how pixels are packed into characters.
+func WithRenderMode(m RenderMode) RenderOption {
+	return func(c *renderConfig) { c.mode = m }
+}
+
+// WithANSIColors - colors output with 24 bit ANSI escape codes, nil color keeps terminal default.
+func WithANSIColors(foreground, background color.Color) RenderOption {
+	return func(c *renderConfig) { c.foreground, c.background = foreground, background }
+}
+
+// WithFitWidth - shrinks image by whole factor until it fits provided number of columns.
+func WithFitWidth(columns int) RenderOption {
+	return func(c *renderConfig) { c.width = columns }
+}
+
+// Render - writes image to terminal using characters packing multiple pixels.
+func (ip *ImagePrinter) Render(w io.Writer, rm PrintableImage, opts ...RenderOption) error {
+	var cfg renderConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cw, ch := cfg.mode.cellSize()
+
+	pp := rm.GetPoints()
+	if len(pp) == 0 {
+		return ErrEmptyImage
+	}
+	if cfg.width > 0 {
+		pp = fitWidth(pp, cfg.width*cw)
+	}
+	bm, err := newBitmap(pp, OriginBottomLeft)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for row := 0; row < bm.h; row += ch {
+		buf.WriteString(ansiColors(cfg.foreground, cfg.background))
+		for col := 0; col < bm.w; col += cw {
+			buf.WriteRune(cellRune(bm, cfg.mode, col, row))
+		}
+		if cfg.foreground != nil || cfg.background != nil {
+			buf.WriteString("\x1b[0m")
+		}
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// fitWidth - shrinks points by smallest whole factor making image at most width pixels wide.
+// Pixels are merged, so thin lines never disappear.
+func fitWidth(pp []Point, width int) []Point {
+	b, _ := Bounds(pp)
+	b = b.Union(Rect{})
+	factor := (b.Width() + width - 1) / width
+	if factor <= 1 {
+		return pp
+	}
+	shrunk := make([]Point, len(pp))
+	for i, p := range pp {
+		shrunk[i] = Point{X: floorDiv(p.X, factor), Y: floorDiv(p.Y, factor)}
+	}
+	return shrunk
+}
+
+// floorDiv - divides rounding towards negative infinity, so negative coordinates shrink consistently.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// brailleDots - bits of braille pattern indexed by [row][column] within cell.
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// cellRune - returns character representing pixels of cell starting at provided column and row.
+func cellRune(bm *bitmap, mode RenderMode, col, row int) rune {
+	at := func(c, r int) bool {
+		return c < bm.w && r < bm.h && bm.at(c, r)
+	}
+	switch mode {
+	case RenderHalfBlock:
+		top, bottom := at(col, row), at(col, row+1)
+		switch {
+		case top && bottom:
+			return '█'
+		case top:
+			return '▀'
+		case bottom:
+			return '▄'
+		}
+		return ' '
+	case RenderBraille:
+		var bits rune
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 2; c++ {
+				if at(col+c, row+r) {
+					bits |= brailleDots[r][c]
+				}
+			}
+		}
+		if bits == 0 {
+			return ' '
+		}
+		return 0x2800 + bits
+	default:
+		if at(col, row) {
+			return '.'
+		}
+		return ' '
+	}
+}
+
+// ansiColors - returns escape codes setting 24 bit foreground and background colors.
+func ansiColors(foreground, background color.Color) string {
+	var s string
+	if foreground != nil {
+		c := color.NRGBAModel.Convert(foreground).(color.NRGBA)
+		s += fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+	}
+	if background != nil {
+		c := color.NRGBAModel.Convert(background).(color.NRGBA)
+		s += fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+	}
+	return s
+}
@@ -0,0 +1,298 @@
+package adapter
+
+import (
+	"math"
+	"sort"
+)
+
+// -- Reverse Adapter
+
+// DefaultTraceTolerance - deviation from traced points a line may have, covers Bresenham rounding.
+const DefaultTraceTolerance = 0.5
+
+// NewRasterToVectorAdapter - creates vector image tracing raster image points back into lines.
+//
+// Filled areas become horizontal spans, one per row. Remaining adjacent points are chained into paths
+// between endpoints and junctions, then every path is simplified with Douglas-Peucker: collinear
+// (horizontal, vertical, diagonal) runs collapse into single line and general slopes are kept within
+// tolerance, which defaults to DefaultTraceTolerance when <= 0. Up to default tolerance lines also
+// rasterize into exactly the traced points, so the image round-trips unchanged. Finally lines broken
+// by junctions are joined back whenever joined line rasterizes into the very same points.
+func NewRasterToVectorAdapter(ri PrintableImage, tolerance float64) *VectorImage {
+	if tolerance <= 0 {
+		tolerance = DefaultTraceTolerance
+	}
+	lines, outline := traceAreas(ri.GetPoints())
+	for _, path := range tracePaths(outline) {
+		if len(path) == 1 {
+			lines = append(lines, NewLine(path[0].X, path[0].Y, path[0].X, path[0].Y))
+			continue
+		}
+		vertices := simplify(path, tolerance, tolerance <= DefaultTraceTolerance)
+		for i := 1; i < len(vertices); i++ {
+			a, b := vertices[i-1], vertices[i]
+			lines = append(lines, NewLine(a.X, a.Y, b.X, b.Y))
+		}
+	}
+
+	vi := NewVectorImage()
+	for _, l := range joinLines(lines) {
+		vi.AddLine(l)
+	}
+	return vi
+}
+
+// traceAreas - splits points into horizontal spans covering filled areas and remaining outline points.
+//
+// Point surrounded by all 8 neighbours lies within filled area, so do its neighbours; thin lines,
+// their corners and crossings never have such point.
+func traceAreas(pp []Point) ([]Line, []Point) {
+	g := newPointGraph(pp)
+	area := make(map[Point]bool)
+	for _, p := range g.points {
+		if len(g.around(p)) == 8 {
+			area[p] = true
+			for _, q := range g.around(p) {
+				area[q] = true
+			}
+		}
+	}
+
+	var spans []Line
+	var outline []Point
+	for i, p := range g.points {
+		if !area[p] {
+			outline = append(outline, p)
+			continue
+		}
+		// points are sorted by rows, span ends where next point is not its area right neighbour
+		if i+1 < len(g.points) && g.points[i+1] == (Point{X: p.X + 1, Y: p.Y}) && area[g.points[i+1]] {
+			continue
+		}
+		start := p
+		for area[Point{X: start.X - 1, Y: start.Y}] {
+			start.X--
+		}
+		spans = append(spans, NewLine(start.X, start.Y, p.X, p.Y))
+	}
+	return spans, outline
+}
+
+// joinLines - repeatedly joins lines touching end to end into single line, as long as it rasterizes
+// into the same points as both of them do.
+func joinLines(ll []Line) []Line {
+	ll = append([]Line(nil), ll...)
+	for joined := true; joined; {
+		joined = false
+		for i := 0; i < len(ll); i++ {
+			for j := i + 1; j < len(ll); j++ {
+				if l, ok := join(ll[i], ll[j]); ok {
+					ll[i] = l
+					ll = append(ll[:j], ll[j+1:]...)
+					j--
+					joined = true
+				}
+			}
+		}
+	}
+	return ll
+}
+
+// join - returns line from far end of a to far end of b, when their near ends touch or coincide
+// and the line rasterizes into exactly the points of a and b.
+func join(a, b Line) (Line, bool) {
+	for _, from := range []Line{a, NewLine(a.X2, a.Y2, a.X1, a.Y1)} {
+		for _, to := range []Line{b, NewLine(b.X2, b.Y2, b.X1, b.Y1)} {
+			// from ends where to starts
+			if abs(from.X2-to.X1) > 1 || abs(from.Y2-to.Y1) > 1 {
+				continue
+			}
+			l := NewLine(from.X1, from.Y1, to.X2, to.Y2)
+			if rasterizesInto(l, append(a.Rasterize(), b.Rasterize()...)) {
+				return l, true
+			}
+		}
+	}
+	return Line{}, false
+}
+
+// rasterizesInto - reports whether line rasterizes into the same points as provided ones, in any order.
+func rasterizesInto(l Line, pp []Point) bool {
+	want := make(map[Point]bool, len(pp))
+	for _, p := range pp {
+		want[p] = true
+	}
+	got := l.Rasterize()
+	if len(got) != len(want) {
+		return false
+	}
+	for _, p := range got {
+		if !want[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// pointGraph - represents points connected to their 8 neighbours.
+type pointGraph struct {
+	points []Point // sorted, so tracing is deterministic
+	set    map[Point]bool
+}
+
+// newPointGraph - creates graph of unique points.
+func newPointGraph(pp []Point) *pointGraph {
+	g := &pointGraph{set: make(map[Point]bool, len(pp))}
+	for _, p := range pp {
+		if !g.set[p] {
+			g.set[p] = true
+			g.points = append(g.points, p)
+		}
+	}
+	sort.Slice(g.points, func(i, j int) bool {
+		if g.points[i].Y != g.points[j].Y {
+			return g.points[i].Y < g.points[j].Y
+		}
+		return g.points[i].X < g.points[j].X
+	})
+	return g
+}
+
+// around - returns all adjacent points.
+func (g *pointGraph) around(p Point) []Point {
+	var nn []Point
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if q := (Point{X: p.X + dx, Y: p.Y + dy}); (dx != 0 || dy != 0) && g.set[q] {
+				nn = append(nn, q)
+			}
+		}
+	}
+	return nn
+}
+
+// neighbours - returns adjacent points; diagonal neighbour reachable through orthogonal corner point
+// is left out, otherwise every corner and staircase would form a triangle.
+func (g *pointGraph) neighbours(p Point) []Point {
+	var nn []Point
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			q := Point{X: p.X + dx, Y: p.Y + dy}
+			if (dx == 0 && dy == 0) || !g.set[q] {
+				continue
+			}
+			if dx != 0 && dy != 0 && (g.set[Point{X: p.X + dx, Y: p.Y}] || g.set[Point{X: p.X, Y: p.Y + dy}]) {
+				continue
+			}
+			nn = append(nn, q)
+		}
+	}
+	return nn
+}
+
+// edge - represents undirected connection between two points.
+type edge struct {
+	a, b Point
+}
+
+// newEdge - creates edge with points in canonical order.
+func newEdge(a, b Point) edge {
+	if b.Y < a.Y || (b.Y == a.Y && b.X < a.X) {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// tracePaths - splits points into chains, each chain runs between endpoints or junctions, or around a loop.
+func tracePaths(pp []Point) [][]Point {
+	g := newPointGraph(pp)
+	visited := make(map[edge]bool)
+	var paths [][]Point
+
+	walk := func(from, next Point) []Point {
+		path := []Point{from, next}
+		visited[newEdge(from, next)] = true
+		for cur := next; ; {
+			nn := g.neighbours(cur)
+			if len(nn) != 2 {
+				return path // endpoint or junction
+			}
+			moved := false
+			for _, n := range nn {
+				if e := newEdge(cur, n); !visited[e] {
+					visited[e] = true
+					path = append(path, n)
+					cur, moved = n, true
+					break
+				}
+			}
+			if !moved {
+				return path // loop closed
+			}
+		}
+	}
+
+	// chains starting at endpoints and junctions first, then remaining loops
+	for _, loops := range []bool{false, true} {
+		for _, p := range g.points {
+			nn := g.neighbours(p)
+			if len(nn) == 0 && !loops {
+				paths = append(paths, []Point{p})
+				continue
+			}
+			if (len(nn) == 2) != loops {
+				continue
+			}
+			for _, n := range nn {
+				if !visited[newEdge(p, n)] {
+					paths = append(paths, walk(p, n))
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// simplify - reduces path to vertices of line segments deviating from it no more than tolerance (Douglas-Peucker),
+// exact segments must also rasterize into the very points of path they replace.
+func simplify(path []Point, tolerance float64, exact bool) []Point {
+	keep := make([]bool, len(path))
+	keep[0], keep[len(path)-1] = true, true
+
+	var split func(first, last int)
+	split = func(first, last int) {
+		farthest, dist := -1, -1.0
+		for i := first + 1; i < last; i++ {
+			if d := distanceToSegment(path[i], path[first], path[last]); d > dist {
+				farthest, dist = i, d
+			}
+		}
+		if farthest < 0 || dist <= tolerance && (!exact ||
+			rasterizesInto(NewLine(path[first].X, path[first].Y, path[last].X, path[last].Y), path[first:last+1])) {
+			return
+		}
+		keep[farthest] = true
+		split(first, farthest)
+		split(farthest, last)
+	}
+	split(0, len(path)-1)
+
+	vertices := make([]Point, 0, len(path))
+	for i, p := range path {
+		if keep[i] {
+			vertices = append(vertices, p)
+		}
+	}
+	return vertices
+}
+
+// distanceToSegment - returns distance from point to segment ab.
+func distanceToSegment(p, a, b Point) float64 {
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	px, py := float64(p.X-a.X), float64(p.Y-a.Y)
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px, py)
+	}
+	t := math.Max(0, math.Min(1, (px*dx+py*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(px-t*dx, py-t*dy)
+}
@@ -0,0 +1,88 @@
+package adapter
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// vectorOf - creates vector image of provided shapes.
+func vectorOf(shapes ...Shape) *VectorImage {
+	vi := NewVectorImage()
+	for _, s := range shapes {
+		if l, ok := s.(Line); ok {
+			vi.AddLine(l)
+			continue
+		}
+		vi.AddShape(s)
+	}
+	return vi
+}
+
+// roundTrip - rasterizes vector image, traces it back and rasterizes traced image again.
+func roundTrip(vi *VectorImage) (traced *VectorImage, before, after map[Point]bool) {
+	raster := NewVectorToRasterAdapter(vi, nil)
+	traced = NewRasterToVectorAdapter(raster, 0)
+	return traced, pointSet(raster.GetPoints()), pointSet(NewVectorToRasterAdapter(traced, nil).GetPoints())
+}
+
+func TestRasterToVectorRoundTrip(t *testing.T) {
+	house, _, err := ImportSVGFile("files/house.svg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		image    *VectorImage
+		maxLines int
+	}{
+		{"point", vectorOf(NewLine(3, 3, 3, 3)), 1},
+		{"horizontal line", vectorOf(NewLine(0, 0, 20, 0)), 1},
+		{"diagonal line", vectorOf(NewLine(0, 10, 10, 0)), 1},
+		{"sloped line", vectorOf(NewLine(0, 0, 17, 6)), 1},
+		{"square", vectorOf(NewPolygon(false, NewPoint(0, 0), NewPoint(10, 0), NewPoint(10, 10), NewPoint(0, 10))), 4},
+		{"crossing lines", vectorOf(NewLine(0, 5, 10, 5), NewLine(5, 0, 5, 10)), 2},
+		{"line broken by junctions", vectorOf(NewLine(0, 9, 30, 9), NewLine(5, 0, 5, 8), NewLine(12, 0, 12, 8), NewLine(20, 0, 20, 8)), 4},
+		{"filled rectangle", vectorOf(NewPolygon(true, NewPoint(0, 0), NewPoint(12, 0), NewPoint(12, 5), NewPoint(0, 5))), 6},
+		{"filled triangle", vectorOf(NewPolygon(true, NewPoint(0, 12), NewPoint(5, 0), NewPoint(10, 12))), 16},
+		{"circle", vectorOf(NewCircle(10, 10, 8)), 32},
+		{"house", house, 40},
+	}
+	for _, tt := range tests {
+		traced, before, after := roundTrip(tt.image)
+		if !reflect.DeepEqual(before, after) {
+			t.Errorf("%s: expected traced image to rasterize into %d original points, got %d points", tt.name, len(before), len(after))
+		}
+		if len(traced.Image) > tt.maxLines {
+			t.Errorf("%s: expected at most %d lines, got %d: %v", tt.name, tt.maxLines, len(traced.Image), traced.Image)
+		}
+	}
+}
+
+// randomLines - represents few random lines, generated for property tests.
+type randomLines []Line
+
+// Generate - generates up to 5 lines within 32x32 area (implements quick.Generator).
+func (randomLines) Generate(r *rand.Rand, size int) reflect.Value {
+	ll := make(randomLines, 1+r.Intn(5))
+	for i := range ll {
+		ll[i] = NewLine(r.Intn(32), r.Intn(32), r.Intn(32), r.Intn(32))
+	}
+	return reflect.ValueOf(ll)
+}
+
+func TestRasterToVectorRoundTripLines(t *testing.T) {
+	f := func(ll randomLines) bool {
+		vi := NewVectorImage()
+		for _, l := range ll {
+			vi.AddLine(l)
+		}
+		_, before, after := roundTrip(vi)
+		return reflect.DeepEqual(before, after)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
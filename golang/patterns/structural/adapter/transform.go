@@ -0,0 +1,288 @@
+package adapter
+
+import (
+	"math"
+	"sync"
+)
+
+// -- Bounds
+
+// Rect - represents rectangle of points, both corners are inclusive.
+type Rect struct {
+	Min, Max Point
+}
+
+// NewRect - creates new instance of Rect from any two opposite corners.
+func NewRect(x1, y1, x2, y2 int) Rect {
+	return Rect{Min: Point{X: min(x1, x2), Y: min(y1, y2)}, Max: Point{X: max(x1, x2), Y: max(y1, y2)}}
+}
+
+// Bounds - returns smallest rectangle containing every point, reports false for no points.
+func Bounds(pp []Point) (Rect, bool) {
+	if len(pp) == 0 {
+		return Rect{}, false
+	}
+	r := Rect{Min: pp[0], Max: pp[0]}
+	for _, p := range pp[1:] {
+		r.Min.X, r.Max.X = min(r.Min.X, p.X), max(r.Max.X, p.X)
+		r.Min.Y, r.Max.Y = min(r.Min.Y, p.Y), max(r.Max.Y, p.Y)
+	}
+	return r, true
+}
+
+// Width - returns number of columns.
+func (r Rect) Width() int {
+	return r.Max.X - r.Min.X + 1
+}
+
+// Height - returns number of rows.
+func (r Rect) Height() int {
+	return r.Max.Y - r.Min.Y + 1
+}
+
+// Contains - reports whether point lies within rectangle.
+func (r Rect) Contains(p Point) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X && p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// Union - returns smallest rectangle containing both rectangles.
+func (r Rect) Union(o Rect) Rect {
+	return Rect{
+		Min: Point{X: min(r.Min.X, o.Min.X), Y: min(r.Min.Y, o.Min.Y)},
+		Max: Point{X: max(r.Max.X, o.Max.X), Y: max(r.Max.Y, o.Max.Y)},
+	}
+}
+
+// -- Transforms
+
+// Transform - represents affine transform of image coordinates.
+type Transform struct {
+	m affine
+}
+
+// Identity - returns transform which leaves points as they are.
+func Identity() Transform {
+	return Transform{identity()}
+}
+
+// Translate - returns transform moving points by provided offset.
+func Translate(dx, dy int) Transform {
+	return Transform{translate(float64(dx), float64(dy))}
+}
+
+// Scale - returns transform scaling points relative to origin, negative factors mirror.
+func Scale(sx, sy float64) Transform {
+	return Transform{affine{a: sx, d: sy}}
+}
+
+// Rotate - returns transform rotating points counterclockwise around origin by provided degrees.
+func Rotate(degrees float64) Transform {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	// multiples of right angle must stay exact
+	sin, cos = math.Round(sin*1e12)/1e12, math.Round(cos*1e12)/1e12
+	return Transform{affine{a: cos, b: sin, c: -sin, d: cos}}
+}
+
+// FlipHorizontal - returns transform mirroring points across y axis.
+func FlipHorizontal() Transform {
+	return Scale(-1, 1)
+}
+
+// FlipVertical - returns transform mirroring points across x axis.
+func FlipVertical() Transform {
+	return Scale(1, -1)
+}
+
+// Then - returns transform applying t first, then next.
+func (t Transform) Then(next Transform) Transform {
+	return Transform{next.m.multiply(t.m)}
+}
+
+// Around - returns transform applied relative to provided point instead of origin, e.g. rotation around center.
+func (t Transform) Around(p Point) Transform {
+	return Translate(-p.X, -p.Y).Then(t).Then(Translate(p.X, p.Y))
+}
+
+// Apply - transforms point, rounding to nearest one.
+func (t Transform) Apply(p Point) Point {
+	x, y := t.m.apply(float64(p.X), float64(p.Y))
+	return Point{X: int(math.Round(x)), Y: int(math.Round(y))}
+}
+
+// transformedImage - represents image adapter transforming points of wrapped image.
+type transformedImage struct {
+	image     PrintableImage
+	transform Transform
+
+	once   sync.Once
+	points []Point
+}
+
+// NewTransformedImage - creates image adapter which transforms points of provided image,
+// points are transformed once on first use, so later changes of wrapped image are not seen.
+func NewTransformedImage(pi PrintableImage, t Transform) PrintableImage {
+	return &transformedImage{image: pi, transform: t}
+}
+
+// GetPoints - gets transformed points (implements PrintableImage).
+func (ti *transformedImage) GetPoints() []Point {
+	ti.once.Do(func() {
+		ti.points = ti.transformPoints(ti.image.GetPoints())
+	})
+	return ti.points
+}
+
+// transformPoints - transforms points treating every point as a unit square.
+//
+// Destination points whose centers map back into a source point are set, so scaled up lines stay solid
+// and rotated lines stay connected. Transform shrinking in any direction may step over source points,
+// so then every source point is also mapped forward and none gets lost.
+func (ti *transformedImage) transformPoints(src []Point) []Point {
+	inv, ok := ti.transform.m.invert()
+	if !ok {
+		// degenerate transform collapses image, map points forward
+		pp := make([]Point, len(src))
+		for i, p := range src {
+			pp[i] = ti.transform.Apply(p)
+		}
+		return pp
+	}
+
+	b, ok := Bounds(src)
+	if !ok {
+		return nil
+	}
+	set := make(map[Point]bool, len(src))
+	for _, p := range src {
+		set[p] = true
+	}
+
+	// destination bounds are transformed corners of the source pixels area
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{
+		{float64(b.Min.X) - 0.5, float64(b.Min.Y) - 0.5}, {float64(b.Max.X) + 0.5, float64(b.Min.Y) - 0.5},
+		{float64(b.Min.X) - 0.5, float64(b.Max.Y) + 0.5}, {float64(b.Max.X) + 0.5, float64(b.Max.Y) + 0.5},
+	} {
+		x, y := ti.transform.m.apply(c[0], c[1])
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	var pp []Point
+	for y := int(math.Ceil(minY)); y <= int(math.Floor(maxY)); y++ {
+		for x := int(math.Ceil(minX)); x <= int(math.Floor(maxX)); x++ {
+			sx, sy := inv.apply(float64(x), float64(y))
+			if set[Point{X: int(math.Round(sx)), Y: int(math.Round(sy))}] {
+				pp = append(pp, Point{X: x, Y: y})
+			}
+		}
+	}
+	if ti.transform.m.minScale() > 1-1e-9 {
+		return pp
+	}
+
+	sampled := make(map[Point]bool, len(pp))
+	for _, p := range pp {
+		sampled[p] = true
+	}
+	for _, p := range src {
+		if q := ti.transform.Apply(p); !sampled[q] {
+			sampled[q] = true
+			pp = append(pp, q)
+		}
+	}
+	return pp
+}
+
+// -- Viewport
+
+// clippedImage - represents image adapter keeping only points within window, optionally moved to origin.
+type clippedImage struct {
+	image  PrintableImage
+	window Rect
+	shift  bool
+}
+
+// NewClippedImage - creates image adapter keeping only points of provided image within window.
+func NewClippedImage(pi PrintableImage, window Rect) PrintableImage {
+	return &clippedImage{image: pi, window: window}
+}
+
+// NewViewport - creates image adapter showing window of provided image, window's bottom left corner becomes origin.
+func NewViewport(pi PrintableImage, window Rect) PrintableImage {
+	return &clippedImage{image: pi, window: window, shift: true}
+}
+
+// GetPoints - gets points within window (implements PrintableImage).
+func (ci *clippedImage) GetPoints() []Point {
+	var pp []Point
+	for _, p := range ci.image.GetPoints() {
+		if !ci.window.Contains(p) {
+			continue
+		}
+		if ci.shift {
+			p = Point{X: p.X - ci.window.Min.X, Y: p.Y - ci.window.Min.Y}
+		}
+		pp = append(pp, p)
+	}
+	return pp
+}
+
+// -- Affine transforms
+
+// affine - represents 2D affine transform [a c e; b d f; 0 0 1] as in SVG matrix(a b c d e f).
+type affine struct {
+	a, b, c, d, e, f float64
+}
+
+// identity - returns transform which leaves points as they are.
+func identity() affine {
+	return affine{a: 1, d: 1}
+}
+
+// translate - returns translation.
+func translate(x, y float64) affine {
+	return affine{a: 1, d: 1, e: x, f: y}
+}
+
+// multiply - returns transform applying t first, then m.
+func (m affine) multiply(t affine) affine {
+	return affine{
+		a: m.a*t.a + m.c*t.b,
+		b: m.b*t.a + m.d*t.b,
+		c: m.a*t.c + m.c*t.d,
+		d: m.b*t.c + m.d*t.d,
+		e: m.a*t.e + m.c*t.f + m.e,
+		f: m.b*t.e + m.d*t.f + m.f,
+	}
+}
+
+// apply - transforms point.
+func (m affine) apply(x, y float64) (float64, float64) {
+	return m.a*x + m.c*y + m.e, m.b*x + m.d*y + m.f
+}
+
+// minScale - returns factor by which transform stretches lengths in direction it stretches least,
+// below 1 when it shrinks.
+func (m affine) minScale() float64 {
+	// smaller singular value of linear part
+	sum := m.a*m.a + m.b*m.b + m.c*m.c + m.d*m.d
+	det := m.a*m.d - m.b*m.c
+	return math.Sqrt(math.Max(0, (sum-math.Sqrt(math.Max(0, sum*sum-4*det*det)))/2))
+}
+
+// invert - returns inverse transform, reports false when transform collapses plane into line or point.
+func (m affine) invert() (affine, bool) {
+	det := m.a*m.d - m.b*m.c
+	if math.Abs(det) < 1e-12 {
+		return affine{}, false
+	}
+	return affine{
+		a: m.d / det,
+		b: -m.b / det,
+		c: -m.c / det,
+		d: m.a / det,
+		e: (m.c*m.f - m.d*m.e) / det,
+		f: (m.b*m.e - m.a*m.f) / det,
+	}, true
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"testing"
+)
+
+// countingImage - represents image counting how many times its points were requested.
+type countingImage struct {
+	RasterImage
+	calls int
+}
+
+// GetPoints - gets points counting calls (implements PrintableImage).
+func (ci *countingImage) GetPoints() []Point {
+	ci.calls++
+	return ci.RasterImage.GetPoints()
+}
+
+func TestTransformedImageKeepsPoints(t *testing.T) {
+	square := NewVectorToRasterAdapter(vectorOf(NewPolygon(false,
+		NewPoint(0, 0), NewPoint(9, 0), NewPoint(9, 9), NewPoint(0, 9))), nil)
+
+	tests := []struct {
+		name  string
+		image PrintableImage
+		t     Transform
+		want  []Point // points which must be set, nil - every source point mapped forward
+	}{
+		{"shrinking single point", &RasterImage{Image: []Point{NewPoint(1, 1)}}, Scale(0.5, 0.5), nil},
+		{"shrinking square", square, Scale(0.3, 0.3), nil},
+		{"shrinking one axis", square, Scale(0.25, 2), nil},
+		{"rotating and shrinking", square, Rotate(30).Then(Scale(0.5, 0.5)), nil},
+		{"scaling up fills gaps", &RasterImage{Image: []Point{NewPoint(1, 1)}}, Scale(2, 2),
+			[]Point{NewPoint(2, 2), NewPoint(1, 2), NewPoint(2, 1), NewPoint(1, 1)}},
+	}
+	for _, tt := range tests {
+		got := pointSet(NewTransformedImage(tt.image, tt.t).GetPoints())
+		want := tt.want
+		if want == nil {
+			for _, p := range tt.image.GetPoints() {
+				want = append(want, tt.t.Apply(p))
+			}
+		}
+		for _, p := range want {
+			if !got[p] {
+				t.Errorf("%s: expected point %v to be set, got %v", tt.name, p, got)
+				break
+			}
+		}
+	}
+}
+
+func TestTransformedImageTransformsOnce(t *testing.T) {
+	src := &countingImage{RasterImage: RasterImage{Image: []Point{NewPoint(0, 0), NewPoint(3, 4)}}}
+	ti := NewTransformedImage(src, Rotate(90))
+	first := ti.GetPoints()
+	second := ti.GetPoints()
+	if src.calls != 1 {
+		t.Errorf("expected source points requested once, got %d", src.calls)
+	}
+	if len(first) != len(second) {
+		t.Errorf("expected same points on every call, got %d and %d", len(first), len(second))
+	}
+}
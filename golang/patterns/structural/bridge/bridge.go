@@ -1,6 +1,11 @@
 package bridge
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"os"
+)
 
 // Bridge: decouples an abstraction from its implementation so that the two can vary independently.
 //
@@ -24,14 +29,110 @@ func Bridge() {
 	// run refined abstraction method that utilize concreate implementors logic
 	c.Render()
 	s.Render()
+
+	// same shapes drawn by both implementors
+	for _, r := range []Renderer{rrn, vrn} {
+		NewCircle(r, 6).MoveTo(20, 8).SetStyle(Style{Stroke: color.Black, Fill: color.Gray{Y: 0xc0}, StrokeWidth: 1}).Render()
+		NewSquare(r, 9).MoveTo(30, 2).Render()
+	}
+
+	// new shapes are described with primitives, so no renderer changes for them
+	gray := Style{Stroke: color.Black, Fill: color.Gray{Y: 0x80}, StrokeWidth: 1}
+	for _, r := range []Renderer{rrn, vrn} {
+		NewTriangle(r, 0, 10, 5, 0, 10, 10).MoveTo(0, 16).Render()
+		NewRectangle(r, 12, 6).MoveTo(12, 18).SetStyle(gray).Render()
+		NewEllipse(r, 8, 4).MoveTo(34, 21).Render()
+		NewRegularPolygon(r, 6, 6).MoveTo(6, 34).SetStyle(gray).Render()
+		NewStar(r, 5, 7, 3).MoveTo(22, 34).SetStyle(Style{Fill: color.Black}).Render()
+	}
+
+	// scene graph groups shapes, group transform and style apply to the whole group
+	house := NewGroup()
+	ground := NewGroup().Translate(0, 42)
+	big := NewGroup().Scale(1.5, 1.5).Translate(16, 40).SetStyle(Style{Stroke: color.Black, Fill: color.Gray{Y: 0xc0}, StrokeWidth: 1})
+	turned := NewGroup().Translate(-6, -7).Rotate(90).Translate(44, 49)
+	scene := NewGroup()
+	for _, err := range []error{
+		house.AddAt(1, NewTriangle(nil, 0, 6, 6, 0, 12, 6)),
+		house.Add(NewRectangle(nil, 10, 8).MoveTo(1, 6)),
+		house.AddAt(2, NewRectangle(nil, 3, 5).MoveTo(6, 9).SetStyle(Style{Fill: color.Black}), NewCircle(nil, 1.5).MoveTo(3.5, 9.5)),
+		ground.Add(house),
+		big.Add(house),
+		turned.Add(house),
+		scene.Add(ground, big, turned),
+	} {
+		if err != nil {
+			fmt.Println(fmt.Errorf("error occurred: %w", err))
+			return
+		}
+	}
+	if err := house.Add(scene); err != nil {
+		fmt.Println(fmt.Errorf("expected error occurred: %w", err))
+	}
+	for _, r := range []Renderer{rrn, vrn} {
+		scene.Render(r)
+	}
+
+	// raster output is in memory canvas, printable as ASCII or exportable as PNG
+	fmt.Print(rrn.Canvas())
+	var png bytes.Buffer
+	if err := rrn.Canvas().WritePNG(&png); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
+	fmt.Printf("exported png: %d bytes, bounds %v\n", png.Len(), rrn.Canvas().Bounds())
+
+	// vector output is SVG document
+	if _, err := vrn.Document().WriteTo(os.Stdout); err != nil {
+		fmt.Println(fmt.Errorf("error occurred: %w", err))
+		return
+	}
 }
 
 // -- Abstraction
 
 type Shape struct {
 	renderer Renderer // abstraction requires implementor
+	style    *Style   // nil - not set, group style or DefaultStyle applies
+}
+
+// Outliner - represents shape describing its outline with renderer primitives.
+type Outliner interface {
+	Outline(r Renderer)
+}
+
+// Style - returns style the shape is painted with.
+func (s *Shape) Style() Style {
+	if st, ok := s.ownStyle(); ok {
+		return st
+	}
+	return DefaultStyle
+}
+
+// ownStyle - returns style set for the shape, reports false when none was set.
+func (s *Shape) ownStyle() (Style, bool) {
+	if s.style == nil {
+		return Style{}, false
+	}
+	return *s.style, true
+}
+
+// render - describes outline with shape renderer and paints it with shape style.
+func (s *Shape) render(o Outliner) {
+	o.Outline(s.renderer)
+	s.renderer.Fill(s.Style())
+}
+
+// Style - represents how shape outline and interior are painted.
+type Style struct {
+	Stroke      color.Color // outline color, nil - no outline
+	Fill        color.Color // interior color, nil - no fill
+	StrokeWidth float64
 }
 
+// DefaultStyle - style of shapes unless set otherwise, thin black outline.
+var DefaultStyle = Style{Stroke: color.Black, StrokeWidth: 1}
+
 // /\
 //
 // Bridge
@@ -41,9 +142,16 @@ type Shape struct {
 // -- Implementor
 
 // Renderer - represents rendering interface (implementor).
+//
+// Renderer knows drawing primitives only, shapes describe themselves with them,
+// so new shape needs no change of any renderer and new renderer supports every shape.
+// Coordinates have y axis pointing down, angles are in degrees and grow clockwise.
 type Renderer interface {
-	RenderCircle(radius float64)
-	RenderSquare(side int)
+	MoveTo(x, y float64)                                // starts new subpath
+	LineTo(x, y float64)                                // adds line from current point
+	Arc(cx, cy, rx, ry, rotation, start, sweep float64) // adds elliptical arc, joined to current point by line
+	ClosePath()                                         // joins current point to subpath start
+	Fill(style Style)                                   // paints path interior and outline, then starts new path
 }
 
 // -- Refined Absctraction.
@@ -51,69 +159,73 @@ type Renderer interface {
 // Circle - represents circle (refined abstraction).
 type Circle struct {
 	Shape
+	x, y   float64
 	radius float64
 }
 
-// NewCircle - creates new instance of a circle.
+// NewCircle - creates new instance of a circle, placed so it touches both axes.
 func NewCircle(renderer Renderer, radius float64) *Circle {
-	return &Circle{Shape{renderer}, radius}
+	return &Circle{Shape{renderer: renderer}, radius, radius, radius}
+}
+
+// MoveTo - places center of the circle.
+func (c *Circle) MoveTo(x, y float64) *Circle {
+	c.x, c.y = x, y
+	return c
+}
+
+// SetStyle - sets style of the circle.
+func (c *Circle) SetStyle(s Style) *Circle {
+	c.style = &s
+	return c
+}
+
+// Outline - describes circle as full turn arc (implements Outliner).
+func (c *Circle) Outline(r Renderer) {
+	r.Arc(c.x, c.y, c.radius, c.radius, 0, 0, 360)
+	r.ClosePath()
 }
 
 // Render - renders circle image.
 func (c *Circle) Render() {
-	c.Shape.renderer.RenderCircle(c.radius)
+	c.render(c)
 }
 
 // Square - represents square (refined abstraction).
 type Square struct {
 	Shape
+	x, y float64
 	side int
 }
 
-// NewSquare - creates new instance of a square.
+// NewSquare - creates new instance of a square, top left corner placed at the origin.
 func NewSquare(renderer Renderer, side int) *Square {
-	return &Square{Shape{renderer}, side}
-}
-
-// Render - renders square image.
-func (s *Square) Render() {
-	s.Shape.renderer.RenderSquare(s.side)
-}
-
-// -- Concreate Implementor
-
-// RasterRenderer - represents raster renderer (concreate implementor).
-type RasterRenderer struct{}
-
-// NewRasterRenderer - creates new instance of raster renderer.
-func NewRasterRenderer() *RasterRenderer {
-	return &RasterRenderer{}
-}
-
-// RenderCircle - renders circle.
-func (rr *RasterRenderer) RenderCircle(radius float64) {
-	fmt.Printf("rendering circle as raster: %f\n", radius)
+	return &Square{Shape{renderer: renderer}, 0, 0, side}
 }
 
-// RenderSquare - renders square.
-func (rr *RasterRenderer) RenderSquare(side int) {
-	fmt.Printf("rendering square as raster: %d\n", side)
+// MoveTo - places top left corner of the square.
+func (s *Square) MoveTo(x, y float64) *Square {
+	s.x, s.y = x, y
+	return s
 }
 
-// VectorRenderer - represents vector renderer (concreate implementor).
-type VectorRenderer struct{}
-
-// NewVectorRenderer - creates new instance of vector renderer.
-func NewVectorRenderer() *VectorRenderer {
-	return &VectorRenderer{}
+// SetStyle - sets style of the square.
+func (s *Square) SetStyle(st Style) *Square {
+	s.style = &st
+	return s
 }
 
-// RenderCircle - renders circle.
-func (vr *VectorRenderer) RenderCircle(radius float64) {
-	fmt.Printf("rendering circle as vector: %f\n", radius)
+// Outline - describes square as closed path (implements Outliner).
+func (s *Square) Outline(r Renderer) {
+	side := float64(s.side)
+	r.MoveTo(s.x, s.y)
+	r.LineTo(s.x+side, s.y)
+	r.LineTo(s.x+side, s.y+side)
+	r.LineTo(s.x, s.y+side)
+	r.ClosePath()
 }
 
-// RenderSquare - renders square.
-func (vr *VectorRenderer) RenderSquare(side int) {
-	fmt.Printf("rendering square as vector: %d\n", side)
+// Render - renders square image.
+func (s *Square) Render() {
+	s.render(s)
 }
@@ -0,0 +1,134 @@
+package bridge
+
+import (
+	"math"
+)
+
+// -- Path
+
+// point - represents position on drawing surface, y axis points down.
+type point struct {
+	x, y float64
+}
+
+// subpath - represents connected run of points, closed subpath joins last point to first one.
+type subpath struct {
+	points []point
+	closed bool
+}
+
+// path - represents outline being built from renderer primitives, arcs are flattened into lines.
+type path struct {
+	subpaths []subpath
+}
+
+// moveTo - starts new subpath.
+func (p *path) moveTo(x, y float64) {
+	p.subpaths = append(p.subpaths, subpath{points: []point{{x, y}}})
+}
+
+// lineTo - adds line to current subpath, starts new one when there is none.
+func (p *path) lineTo(x, y float64) {
+	if len(p.subpaths) == 0 || p.subpaths[len(p.subpaths)-1].closed {
+		p.moveTo(x, y)
+		return
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.points = append(sp.points, point{x, y})
+}
+
+// arc - adds elliptical arc as line segments no longer than half of a pixel.
+func (p *path) arc(cx, cy, rx, ry, rotation, start, sweep float64) {
+	steps := arcSteps(sweep, math.Max(rx, ry))
+	for i := 0; i <= steps; i++ {
+		x, y := arcPoint(cx, cy, rx, ry, rotation, start+sweep*float64(i)/float64(steps))
+		p.lineTo(x, y)
+	}
+}
+
+// arcSteps - returns number of lines approximating arc, so none is longer than half of a pixel.
+func arcSteps(sweep, radius float64) int {
+	steps := int(math.Ceil(math.Abs(sweep) * math.Pi / 180 * radius * 2))
+	if steps < 8 {
+		steps = 8
+	}
+	return steps
+}
+
+// closePath - closes current subpath.
+func (p *path) closePath() {
+	if len(p.subpaths) > 0 {
+		p.subpaths[len(p.subpaths)-1].closed = true
+	}
+}
+
+// reset - discards path.
+func (p *path) reset() {
+	p.subpaths = nil
+}
+
+// bounds - returns smallest area containing every point, reports false for empty path.
+func (p *path) bounds() (x0, y0, x1, y1 float64, ok bool) {
+	x0, y0, x1, y1 = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, sp := range p.subpaths {
+		for _, pt := range sp.points {
+			x0, y0 = math.Min(x0, pt.x), math.Min(y0, pt.y)
+			x1, y1 = math.Max(x1, pt.x), math.Max(y1, pt.y)
+			ok = true
+		}
+	}
+	return x0, y0, x1, y1, ok
+}
+
+// segments - calls fn for every line of the path, reporting whether line belongs to closed subpath.
+func (p *path) segments(fn func(a, b point, closed bool)) {
+	for _, sp := range p.subpaths {
+		for i := 1; i < len(sp.points); i++ {
+			fn(sp.points[i-1], sp.points[i], sp.closed)
+		}
+		if sp.closed && len(sp.points) > 1 {
+			fn(sp.points[len(sp.points)-1], sp.points[0], true)
+		}
+	}
+}
+
+// contains - reports whether point lies inside path using nonzero winding rule, subpaths are closed implicitly.
+func (p *path) contains(x, y float64) bool {
+	winding := 0
+	for _, sp := range p.subpaths {
+		for i := range sp.points {
+			a, b := sp.points[i], sp.points[(i+1)%len(sp.points)]
+			switch {
+			case a.y <= y && b.y > y && cross(a, b, x, y) > 0:
+				winding++
+			case a.y > y && b.y <= y && cross(a, b, x, y) < 0:
+				winding--
+			}
+		}
+	}
+	return winding != 0
+}
+
+// cross - returns which side of line ab the point lies on.
+func cross(a, b point, x, y float64) float64 {
+	return (b.x-a.x)*(y-a.y) - (x-a.x)*(b.y-a.y)
+}
+
+// distance - returns distance from point to segment ab.
+func distance(x, y float64, a, b point) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	px, py := x-a.x, y-a.y
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px, py)
+	}
+	t := math.Max(0, math.Min(1, (px*dx+py*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(px-t*dx, py-t*dy)
+}
+
+// arcPoint - returns point of ellipse rotated by rotation degrees at provided angle in degrees.
+func arcPoint(cx, cy, rx, ry, rotation, angle float64) (float64, float64) {
+	sinT, cosT := math.Sincos(angle * math.Pi / 180)
+	sinR, cosR := math.Sincos(rotation * math.Pi / 180)
+	x, y := rx*cosT, ry*sinT
+	return cx + x*cosR - y*sinR, cy + x*sinR + y*cosR
+}
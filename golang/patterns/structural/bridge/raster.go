@@ -0,0 +1,164 @@
+package bridge
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+// -- Concreate Implementor
+
+// RasterRenderer - represents raster rendering (concreate implementor), paints pixels onto canvas.
+type RasterRenderer struct {
+	canvas *Canvas
+	path   path
+}
+
+// NewRasterRenderer - creates new instance of RasterRenderer with empty canvas.
+func NewRasterRenderer() *RasterRenderer {
+	return &RasterRenderer{canvas: NewCanvas()}
+}
+
+// Canvas - returns canvas shapes are painted onto.
+func (r *RasterRenderer) Canvas() *Canvas {
+	return r.canvas
+}
+
+// MoveTo - starts new subpath (implements Renderer).
+func (r *RasterRenderer) MoveTo(x, y float64) {
+	r.path.moveTo(x, y)
+}
+
+// LineTo - adds line to current subpath (implements Renderer).
+func (r *RasterRenderer) LineTo(x, y float64) {
+	r.path.lineTo(x, y)
+}
+
+// Arc - adds elliptical arc to current subpath (implements Renderer).
+func (r *RasterRenderer) Arc(cx, cy, rx, ry, rotation, start, sweep float64) {
+	r.path.arc(cx, cy, rx, ry, rotation, start, sweep)
+}
+
+// ClosePath - closes current subpath (implements Renderer).
+func (r *RasterRenderer) ClosePath() {
+	r.path.closePath()
+}
+
+// Fill - paints path and starts new one, pixel belongs to the shape when its center does (implements Renderer).
+//
+// Stroke is centered on the outline, where outline lies exactly on pixel edge the inner pixel is painted,
+// so outline is one pixel thick instead of two.
+func (r *RasterRenderer) Fill(style Style) {
+	defer r.path.reset()
+	x0, y0, x1, y1, ok := r.path.bounds()
+	if !ok {
+		return
+	}
+	half := style.StrokeWidth / 2
+	for y := int(math.Floor(y0 - half)); y <= int(math.Ceil(y1+half)); y++ {
+		for x := int(math.Floor(x0 - half)); x <= int(math.Ceil(x1+half)); x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			inside := r.path.contains(px, py)
+			if style.Stroke != nil && half > 0 && r.onStroke(px, py, half, inside) {
+				r.canvas.Set(x, y, style.Stroke)
+			} else if style.Fill != nil && inside {
+				r.canvas.Set(x, y, style.Fill)
+			}
+		}
+	}
+}
+
+// onStroke - reports whether point is covered by stroke of provided half width.
+func (r *RasterRenderer) onStroke(x, y, half float64, inside bool) bool {
+	const eps = 1e-9
+	covered := false
+	r.path.segments(func(a, b point, closed bool) {
+		d := distance(x, y, a, b)
+		switch {
+		case d < half-eps:
+			covered = true
+		case d < half+eps && (inside || !closed):
+			covered = true
+		}
+	})
+	return covered
+}
+
+// -- Canvas
+
+// Canvas - represents in memory pixel canvas growing with painted pixels, y axis points down.
+type Canvas struct {
+	pixels map[image.Point]color.Color
+}
+
+// NewCanvas - creates new instance of empty Canvas.
+func NewCanvas() *Canvas {
+	return &Canvas{pixels: make(map[image.Point]color.Color)}
+}
+
+// Set - paints pixel.
+func (c *Canvas) Set(x, y int, col color.Color) {
+	c.pixels[image.Pt(x, y)] = col
+}
+
+// At - returns color of pixel, nil when pixel was not painted.
+func (c *Canvas) At(x, y int) color.Color {
+	return c.pixels[image.Pt(x, y)]
+}
+
+// Bounds - returns smallest rectangle containing every painted pixel.
+func (c *Canvas) Bounds() image.Rectangle {
+	var b image.Rectangle
+	for p := range c.pixels {
+		b = b.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+	}
+	return b
+}
+
+// Image - returns canvas as image, unpainted pixels are transparent.
+func (c *Canvas) Image() *image.NRGBA {
+	img := image.NewNRGBA(c.Bounds())
+	for p, col := range c.pixels {
+		img.Set(p.X, p.Y, col)
+	}
+	return img
+}
+
+// WritePNG - writes canvas encoded as PNG.
+func (c *Canvas) WritePNG(w io.Writer) error {
+	return png.Encode(w, c.Image())
+}
+
+// asciiRamp - characters from darkest to lightest.
+const asciiRamp = "@%#*+=-:. "
+
+// String - returns canvas as ASCII art, darker pixels use denser characters (implements fmt.Stringer).
+func (c *Canvas) String() string {
+	b := c.Bounds()
+	var sb strings.Builder
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		line := make([]byte, 0, b.Dx())
+		for x := b.Min.X; x < b.Max.X; x++ {
+			line = append(line, asciiChar(c.At(x, y)))
+		}
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// asciiChar - returns character for color composed over white background.
+func asciiChar(col color.Color) byte {
+	if col == nil {
+		return ' '
+	}
+	r, g, b, a := col.RGBA()
+	// premultiplied components over white
+	white := float64(0xffff - a)
+	lum := (0.299*(float64(r)+white) + 0.587*(float64(g)+white) + 0.114*(float64(b)+white)) / 0xffff
+	i := int(math.Round(lum * float64(len(asciiRamp)-1)))
+	return asciiRamp[i]
+}
@@ -0,0 +1,57 @@
+package bridge
+
+import (
+	"flag"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// golden - compares output with testdata/name, rewriting the file instead when run with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s: output differs from golden file, run go test -update to review\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// goldenScenes - represents shapes drawn into golden files, each by provided renderer.
+var goldenScenes = []struct {
+	name string
+	draw func(r Renderer)
+}{
+	{"circle", func(r Renderer) { NewCircle(r, 5).Render() }},
+	{"circle-filled", func(r Renderer) {
+		NewCircle(r, 6).MoveTo(8, 7).SetStyle(Style{Stroke: color.Black, Fill: color.Gray{Y: 0xc0}, StrokeWidth: 1}).Render()
+	}},
+	{"square", func(r Renderer) { NewSquare(r, 5).Render() }},
+	{"square-filled", func(r Renderer) {
+		NewSquare(r, 9).MoveTo(2, 1).SetStyle(Style{Stroke: color.Black, Fill: color.Gray{Y: 0x80}, StrokeWidth: 2}).Render()
+	}},
+	{"circle-and-square", func(r Renderer) {
+		NewSquare(r, 10).MoveTo(0, 0).SetStyle(Style{Fill: color.Gray{Y: 0xc0}}).Render()
+		NewCircle(r, 4).MoveTo(10, 10).Render()
+	}},
+}
+
+func TestCanvasString(t *testing.T) {
+	for _, sc := range goldenScenes {
+		r := NewRasterRenderer()
+		sc.draw(r)
+		golden(t, sc.name+".txt", []byte(r.Canvas().String()))
+	}
+}
@@ -0,0 +1,250 @@
+package bridge
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// -- Scene Graph
+
+// Group - represents scene graph node composing shapes and nested groups (composite of abstractions).
+//
+// Group transforms and style apply to everything within, children are painted in z order,
+// so the whole drawing is rendered in one pass by any Renderer.
+type Group struct {
+	children  []sceneChild
+	transform matrix
+	style     *Style
+}
+
+// sceneChild - represents child of a group with its z order.
+type sceneChild struct {
+	node Outliner
+	z    int
+}
+
+// styled - represents node which may have own style set, e.g. any shape.
+type styled interface {
+	ownStyle() (Style, bool)
+}
+
+// ErrGroupCycle - group added into itself or any group within it, such scene would never end.
+var ErrGroupCycle = errors.New("group cycle")
+
+// NewGroup - creates new instance of empty Group with no transform.
+func NewGroup() *Group {
+	return &Group{transform: unit}
+}
+
+// Add - adds shapes or groups at z order 0, shape renderer is not used within scene and may be nil.
+func (g *Group) Add(nodes ...Outliner) error {
+	return g.AddAt(0, nodes...)
+}
+
+// AddAt - adds shapes or groups at provided z order, higher z is painted later, equal z keep order of adding.
+// Group may be added to many groups, but not into itself or any group within it, then nothing is added.
+func (g *Group) AddAt(z int, nodes ...Outliner) error {
+	for _, n := range nodes {
+		if sub, ok := n.(*Group); ok && sub.contains(g) {
+			return ErrGroupCycle
+		}
+	}
+	for _, n := range nodes {
+		g.children = append(g.children, sceneChild{node: n, z: z})
+	}
+	return nil
+}
+
+// contains - reports whether group is provided one or has it anywhere within.
+func (g *Group) contains(target *Group) bool {
+	if g == target {
+		return true
+	}
+	for _, c := range g.children {
+		if sub, ok := c.node.(*Group); ok && sub.contains(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Translate - moves group by provided offset, after transforms set so far.
+func (g *Group) Translate(dx, dy float64) *Group {
+	g.transform = g.transform.then(matrix{a: 1, d: 1, e: dx, f: dy})
+	return g
+}
+
+// Rotate - rotates group clockwise around its origin by provided degrees, after transforms set so far.
+func (g *Group) Rotate(degrees float64) *Group {
+	sin, cos := sincos(degrees)
+	g.transform = g.transform.then(matrix{a: cos, b: sin, c: -sin, d: cos})
+	return g
+}
+
+// Scale - scales group relative to its origin, after transforms set so far, negative factors mirror.
+func (g *Group) Scale(sx, sy float64) *Group {
+	g.transform = g.transform.then(matrix{a: sx, d: sy})
+	return g
+}
+
+// SetStyle - paints shapes within group with provided style, unless the shape or nested group sets its own.
+func (g *Group) SetStyle(s Style) *Group {
+	g.style = &s
+	return g
+}
+
+// Outline - describes outlines of all children as one compound path (implements Outliner).
+func (g *Group) Outline(r Renderer) {
+	tr := &transformRenderer{renderer: r, m: g.transform}
+	for _, c := range g.sorted() {
+		c.node.Outline(tr)
+	}
+}
+
+// Render - renders whole group with provided renderer.
+func (g *Group) Render(r Renderer) {
+	g.render(r, unit, nil)
+}
+
+// render - renders children with transform and style inherited from parent groups.
+func (g *Group) render(r Renderer, parent matrix, style *Style) {
+	m := g.transform.then(parent)
+	if g.style != nil {
+		style = g.style
+	}
+	for _, c := range g.sorted() {
+		if sub, ok := c.node.(*Group); ok {
+			sub.render(r, m, style)
+			continue
+		}
+		tr := &transformRenderer{renderer: r, m: m}
+		c.node.Outline(tr)
+		tr.Fill(styleOf(c.node, style))
+	}
+}
+
+// styleOf - returns style node is painted with: its own, otherwise inherited from groups, otherwise DefaultStyle.
+func styleOf(node Outliner, inherited *Style) Style {
+	if st, ok := node.(styled); ok {
+		if s, ok := st.ownStyle(); ok {
+			return s
+		}
+	}
+	if inherited != nil {
+		return *inherited
+	}
+	return DefaultStyle
+}
+
+// sorted - returns children in z order.
+func (g *Group) sorted() []sceneChild {
+	cc := make([]sceneChild, len(g.children))
+	copy(cc, g.children)
+	sort.SliceStable(cc, func(i, j int) bool { return cc[i].z < cc[j].z })
+	return cc
+}
+
+// -- Transforming Renderer
+
+// transformRenderer - represents renderer decorator transforming primitives before passing them on.
+//
+// Arcs stay arcs under similarity transforms (translation, rotation, uniform scale, mirroring),
+// other transforms turn them into lines.
+type transformRenderer struct {
+	renderer Renderer
+	m        matrix
+}
+
+// MoveTo - starts new subpath at transformed point (implements Renderer).
+func (t *transformRenderer) MoveTo(x, y float64) {
+	t.renderer.MoveTo(t.m.point(x, y))
+}
+
+// LineTo - adds line to transformed point (implements Renderer).
+func (t *transformRenderer) LineTo(x, y float64) {
+	t.renderer.LineTo(t.m.point(x, y))
+}
+
+// Arc - adds transformed elliptical arc (implements Renderer).
+func (t *transformRenderer) Arc(cx, cy, rx, ry, rotation, start, sweep float64) {
+	const eps = 1e-9
+	scale := math.Sqrt(math.Abs(t.m.det()))
+	angle := math.Atan2(t.m.b, t.m.a) * 180 / math.Pi
+	x, y := t.m.point(cx, cy)
+	switch {
+	case math.Abs(t.m.a-t.m.d) < eps && math.Abs(t.m.b+t.m.c) < eps:
+		t.renderer.Arc(x, y, rx*scale, ry*scale, rotation+angle, start, sweep)
+	case math.Abs(t.m.a+t.m.d) < eps && math.Abs(t.m.b-t.m.c) < eps:
+		// mirroring reverses direction of angles
+		t.renderer.Arc(x, y, rx*scale, ry*scale, angle-rotation, -start, -sweep)
+	default:
+		steps := arcSteps(sweep, math.Max(rx, ry)*t.m.maxScale())
+		for i := 0; i <= steps; i++ {
+			t.LineTo(arcPoint(cx, cy, rx, ry, rotation, start+sweep*float64(i)/float64(steps)))
+		}
+	}
+}
+
+// ClosePath - closes current subpath (implements Renderer).
+func (t *transformRenderer) ClosePath() {
+	t.renderer.ClosePath()
+}
+
+// Fill - paints path, stroke width follows average scale of transform (implements Renderer).
+func (t *transformRenderer) Fill(style Style) {
+	style.StrokeWidth *= math.Sqrt(math.Abs(t.m.det()))
+	t.renderer.Fill(style)
+}
+
+// -- Transform Matrix
+
+// matrix - represents transform of scene points: x' = a*x + c*y + e, y' = b*x + d*y + f.
+type matrix struct {
+	a, b, c, d, e, f float64
+}
+
+// unit - matrix leaving points where they are.
+var unit = matrix{a: 1, d: 1}
+
+// then - returns matrix transforming by m first, by next afterwards.
+func (m matrix) then(next matrix) matrix {
+	return matrix{
+		a: next.a*m.a + next.c*m.b,
+		b: next.b*m.a + next.d*m.b,
+		c: next.a*m.c + next.c*m.d,
+		d: next.b*m.c + next.d*m.d,
+		e: next.a*m.e + next.c*m.f + next.e,
+		f: next.b*m.e + next.d*m.f + next.f,
+	}
+}
+
+// point - returns transformed point.
+func (m matrix) point(x, y float64) (float64, float64) {
+	return m.a*x + m.c*y + m.e, m.b*x + m.d*y + m.f
+}
+
+// det - returns determinant, factor by which matrix scales areas.
+func (m matrix) det() float64 {
+	return m.a*m.d - m.b*m.c
+}
+
+// maxScale - returns upper bound of factor by which matrix stretches lengths.
+func (m matrix) maxScale() float64 {
+	return math.Max(math.Hypot(m.a, m.b), math.Hypot(m.c, m.d)) * math.Sqrt2
+}
+
+// sincos - returns sine and cosine of angle in degrees, exact for quarter turns so shapes stay on whole pixels.
+func sincos(degrees float64) (sin, cos float64) {
+	switch math.Mod(math.Mod(degrees, 360)+360, 360) {
+	case 0:
+		return 0, 1
+	case 90:
+		return 1, 0
+	case 180:
+		return 0, -1
+	case 270:
+		return -1, 0
+	}
+	return math.Sincos(degrees * math.Pi / 180)
+}
@@ -0,0 +1,237 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// group - creates group of provided nodes, failing the test when they can not be added.
+func group(t *testing.T, g *Group, nodes ...Outliner) *Group {
+	t.Helper()
+	if err := g.Add(nodes...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return g
+}
+
+func TestGroupStyle(t *testing.T) {
+	black := Style{Fill: color.Black}
+	gray := Style{Stroke: color.Black, Fill: color.Gray{Y: 0xc0}, StrokeWidth: 1}
+	white := Style{Fill: color.White}
+
+	tests := []struct {
+		name  string
+		scene *Group
+		want  []Style
+	}{
+		{"shapes keep their style", group(t, NewGroup(), NewSquare(nil, 2).SetStyle(black), NewCircle(nil, 1)),
+			[]Style{black, DefaultStyle}},
+		{"group style paints shapes without style", group(t, NewGroup().SetStyle(gray), NewSquare(nil, 2).SetStyle(black), NewCircle(nil, 1)),
+			[]Style{black, gray}},
+		{"nested group style wins", group(t, NewGroup().SetStyle(gray), group(t, NewGroup().SetStyle(white), NewCircle(nil, 1))),
+			[]Style{white}},
+		{"outer group style is inherited", group(t, NewGroup().SetStyle(gray), group(t, NewGroup(), NewCircle(nil, 1))),
+			[]Style{gray}},
+		{"stroke width follows scale", group(t, NewGroup().Scale(3, 3), NewCircle(nil, 1).SetStyle(gray)),
+			[]Style{{Stroke: gray.Stroke, Fill: gray.Fill, StrokeWidth: 3}}},
+	}
+	for _, tt := range tests {
+		rec := &recorder{}
+		tt.scene.Render(rec)
+		if !reflect.DeepEqual(rec.styles, tt.want) {
+			t.Errorf("%s: expected styles %v, got %v", tt.name, tt.want, rec.styles)
+		}
+	}
+}
+
+func TestGroupZOrder(t *testing.T) {
+	// squares are told apart by their top left corner
+	square := func(x float64) *Square { return NewSquare(nil, 1).MoveTo(x, 0) }
+	nested := NewGroup()
+	for _, err := range []error{
+		nested.AddAt(5, square(10)),
+		nested.AddAt(-5, square(11)),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		add  func(g *Group) error
+		want []float64
+	}{
+		{"equal z keep order of adding", func(g *Group) error { return g.Add(square(1), square(2), square(3)) }, []float64{1, 2, 3}},
+		{"higher z is painted later", func(g *Group) error {
+			if err := g.AddAt(2, square(1)); err != nil {
+				return err
+			}
+			if err := g.AddAt(-1, square(2)); err != nil {
+				return err
+			}
+			return g.Add(square(3))
+		}, []float64{2, 3, 1}},
+		{"nested group is painted at its own z", func(g *Group) error {
+			if err := g.AddAt(1, square(1)); err != nil {
+				return err
+			}
+			return g.AddAt(0, nested, square(2))
+		}, []float64{11, 10, 2, 1}},
+	}
+	for _, tt := range tests {
+		g := NewGroup()
+		if err := tt.add(g); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		rec := &recorder{}
+		g.Render(rec)
+		var got []float64
+		for _, c := range rec.calls {
+			var x, y float64
+			if _, err := fmt.Sscanf(c, "M %g %g", &x, &y); err == nil {
+				got = append(got, x)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected squares painted in order %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGroupTransforms(t *testing.T) {
+	tests := []struct {
+		name  string
+		scene func() *Group
+		want  []string
+	}{
+		{"translate", func() *Group { return group(t, NewGroup().Translate(3, -2), NewSquare(nil, 2)) },
+			[]string{"M 3 -2", "L 5 -2", "L 5 0", "L 3 0", "Z", "F"}},
+		{"rotate quarter turn clockwise", func() *Group { return group(t, NewGroup().Rotate(90), NewSquare(nil, 2)) },
+			[]string{"M 0 0", "L 0 2", "L -2 2", "L -2 0", "Z", "F"}},
+		{"rotate negative half turn", func() *Group { return group(t, NewGroup().Rotate(-180), NewSquare(nil, 2)) },
+			[]string{"M 0 0", "L -2 0", "L -2 -2", "L 0 -2", "Z", "F"}},
+		{"scale non uniformly", func() *Group { return group(t, NewGroup().Scale(2, 3), NewSquare(nil, 2)) },
+			[]string{"M 0 0", "L 4 0", "L 4 6", "L 0 6", "Z", "F"}},
+		{"transforms apply in order of calls", func() *Group { return group(t, NewGroup().Translate(1, 0).Rotate(90), NewSquare(nil, 1)) },
+			[]string{"M 0 1", "L 0 2", "L -1 2", "L -1 1", "Z", "F"}},
+		{"nested transform applies before outer one", func() *Group {
+			return group(t, NewGroup().Scale(2, 2), group(t, NewGroup().Translate(1, 1), NewSquare(nil, 1)))
+		}, []string{"M 2 2", "L 4 2", "L 4 4", "L 2 4", "Z", "F"}},
+		{"arc under uniform scale and rotation stays arc", func() *Group {
+			return group(t, NewGroup().Scale(2, 2).Rotate(90).Translate(10, 0), NewEllipse(nil, 3, 1))
+		}, []string{"A 8 6 6 2 90 0 360", "Z", "F"}},
+	}
+	for _, tt := range tests {
+		rec := &recorder{}
+		tt.scene().Render(rec)
+		if !reflect.DeepEqual(rec.calls, tt.want) {
+			t.Errorf("%s: expected primitives %q, got %q", tt.name, tt.want, rec.calls)
+		}
+	}
+}
+
+func TestGroupTransformsArcs(t *testing.T) {
+	const eps = 1e-9
+	// arc of ellipse centered at (4, 2) with radii 3 and 1, rotated by 30 degrees
+	arc := func(angle float64) (float64, float64) { return arcPoint(4, 2, 3, 1, 30, angle) }
+	tests := []struct {
+		name      string
+		transform func(g *Group) *Group
+		m         matrix // same transform as matrix, to check against
+		keepsArc  bool
+	}{
+		{"mirror horizontally", func(g *Group) *Group { return g.Scale(-1, 1) }, matrix{a: -1, d: 1}, true},
+		{"mirror vertically and rotate", func(g *Group) *Group { return g.Scale(1, -1).Rotate(45) },
+			matrix{a: 1, d: -1}.then(matrix{a: math.Sqrt2 / 2, b: math.Sqrt2 / 2, c: -math.Sqrt2 / 2, d: math.Sqrt2 / 2}), true},
+		{"scale non uniformly", func(g *Group) *Group { return g.Scale(2, 0.5) }, matrix{a: 2, d: 0.5}, false},
+		{"shear by rotated non uniform scale", func(g *Group) *Group { return g.Rotate(30).Scale(1, 3) },
+			matrix{a: math.Sqrt(3) / 2, b: 0.5, c: -0.5, d: math.Sqrt(3) / 2}.then(matrix{a: 1, d: 3}), false},
+	}
+	for _, tt := range tests {
+		rec := &recorder{}
+		g := tt.transform(NewGroup())
+		if err := g.Add(outliner(func(r Renderer) { r.Arc(4, 2, 3, 1, 30, 10, 200) })); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		g.Render(rec)
+
+		if !tt.keepsArc {
+			if len(rec.arcs) != 0 || len(rec.points) < 8 {
+				t.Errorf("%s: expected arc flattened into lines, got %q", tt.name, rec.calls)
+				continue
+			}
+			// every point lies on transformed ellipse, at growing angle from start to end of the arc
+			for i, p := range rec.points {
+				x, y := tt.m.point(arc(10 + 200*float64(i)/float64(len(rec.points)-1)))
+				if math.Abs(p.x-x) > eps || math.Abs(p.y-y) > eps {
+					t.Errorf("%s: expected point %d at (%g, %g), got (%g, %g)", tt.name, i, x, y, p.x, p.y)
+					break
+				}
+			}
+			continue
+		}
+
+		if len(rec.arcs) != 1 {
+			t.Errorf("%s: expected single arc, got %q", tt.name, rec.calls)
+			continue
+		}
+		a := rec.arcs[0]
+		for _, f := range []float64{0, 0.25, 0.5, 1} {
+			wx, wy := tt.m.point(arc(10 + 200*f))
+			gx, gy := arcPoint(a[0], a[1], a[2], a[3], a[4], a[5]+a[6]*f)
+			if math.Abs(gx-wx) > eps || math.Abs(gy-wy) > eps {
+				t.Errorf("%s: expected arc through (%g, %g) at %g of sweep, got (%g, %g)", tt.name, wx, wy, f, gx, gy)
+			}
+		}
+	}
+}
+
+// outliner - represents outline described by function (implements Outliner).
+type outliner func(r Renderer)
+
+// Outline - describes outline (implements Outliner).
+func (o outliner) Outline(r Renderer) {
+	o(r)
+}
+
+func TestGroupRejectsCycles(t *testing.T) {
+	shared := group(t, NewGroup(), NewCircle(nil, 1))
+	outer := group(t, NewGroup(), shared, shared)
+	inner := NewGroup()
+	middle := group(t, NewGroup(), inner)
+	top := group(t, NewGroup(), middle)
+
+	tests := []struct {
+		name string
+		add  func() error
+		want error
+	}{
+		{"group into itself", func() error { return outer.Add(outer) }, ErrGroupCycle},
+		{"group into group within it", func() error { return inner.Add(top) }, ErrGroupCycle},
+		{"group into nested group of its child", func() error { return inner.AddAt(3, NewCircle(nil, 1), middle) }, ErrGroupCycle},
+		{"shared group into another group", func() error { return NewGroup().Add(shared) }, nil},
+		{"group into its sibling", func() error { return outer.Add(group(t, NewGroup(), inner)) }, nil},
+	}
+	for _, tt := range tests {
+		if err := tt.add(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// rejected nodes are not added at all, shared group is painted once for every time it was added
+	for _, tc := range []struct {
+		g    *Group
+		want int
+	}{{top, 0}, {outer, 2}} {
+		rec := &recorder{}
+		tc.g.Render(rec)
+		if got := len(rec.styles); got != tc.want {
+			t.Errorf("expected %d shapes rendered, got %d", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package bridge
+
+import (
+	"math"
+)
+
+// -- Refined Absctraction, described with primitives only.
+
+// Triangle - represents triangle (refined abstraction).
+type Triangle struct {
+	Shape
+	x, y     float64
+	vertices [3]point
+}
+
+// NewTriangle - creates new instance of a triangle with vertices relative to its position, placed at the origin.
+func NewTriangle(renderer Renderer, x1, y1, x2, y2, x3, y3 float64) *Triangle {
+	return &Triangle{Shape: Shape{renderer: renderer}, vertices: [3]point{{x1, y1}, {x2, y2}, {x3, y3}}}
+}
+
+// MoveTo - places the triangle, vertices are relative to this point.
+func (t *Triangle) MoveTo(x, y float64) *Triangle {
+	t.x, t.y = x, y
+	return t
+}
+
+// SetStyle - sets style of the triangle.
+func (t *Triangle) SetStyle(s Style) *Triangle {
+	t.style = &s
+	return t
+}
+
+// Outline - describes triangle as closed path (implements Outliner).
+func (t *Triangle) Outline(r Renderer) {
+	outlinePolygon(r, t.x, t.y, t.vertices[:])
+}
+
+// Render - renders triangle image.
+func (t *Triangle) Render() {
+	t.render(t)
+}
+
+// Rectangle - represents rectangle (refined abstraction).
+type Rectangle struct {
+	Shape
+	x, y          float64
+	width, height float64
+}
+
+// NewRectangle - creates new instance of a rectangle, top left corner placed at the origin.
+func NewRectangle(renderer Renderer, width, height float64) *Rectangle {
+	return &Rectangle{Shape: Shape{renderer: renderer}, width: width, height: height}
+}
+
+// MoveTo - places top left corner of the rectangle.
+func (rc *Rectangle) MoveTo(x, y float64) *Rectangle {
+	rc.x, rc.y = x, y
+	return rc
+}
+
+// SetStyle - sets style of the rectangle.
+func (rc *Rectangle) SetStyle(s Style) *Rectangle {
+	rc.style = &s
+	return rc
+}
+
+// Outline - describes rectangle as closed path (implements Outliner).
+func (rc *Rectangle) Outline(r Renderer) {
+	outlinePolygon(r, rc.x, rc.y, []point{{0, 0}, {rc.width, 0}, {rc.width, rc.height}, {0, rc.height}})
+}
+
+// Render - renders rectangle image.
+func (rc *Rectangle) Render() {
+	rc.render(rc)
+}
+
+// Ellipse - represents axis aligned ellipse (refined abstraction).
+type Ellipse struct {
+	Shape
+	x, y   float64
+	rx, ry float64
+}
+
+// NewEllipse - creates new instance of an ellipse, placed so it touches both axes.
+func NewEllipse(renderer Renderer, rx, ry float64) *Ellipse {
+	return &Ellipse{Shape{renderer: renderer}, rx, ry, rx, ry}
+}
+
+// MoveTo - places center of the ellipse.
+func (e *Ellipse) MoveTo(x, y float64) *Ellipse {
+	e.x, e.y = x, y
+	return e
+}
+
+// SetStyle - sets style of the ellipse.
+func (e *Ellipse) SetStyle(s Style) *Ellipse {
+	e.style = &s
+	return e
+}
+
+// Outline - describes ellipse as full turn arc (implements Outliner).
+func (e *Ellipse) Outline(r Renderer) {
+	r.Arc(e.x, e.y, e.rx, e.ry, 0, 0, 360)
+	r.ClosePath()
+}
+
+// Render - renders ellipse image.
+func (e *Ellipse) Render() {
+	e.render(e)
+}
+
+// RegularPolygon - represents polygon with equal sides pointing up (refined abstraction).
+type RegularPolygon struct {
+	Shape
+	x, y   float64
+	sides  int
+	radius float64
+}
+
+// NewRegularPolygon - creates new instance of a regular polygon inscribed in circle of provided radius,
+// placed so the circle touches both axes. Polygon with less than 3 sides renders nothing.
+func NewRegularPolygon(renderer Renderer, sides int, radius float64) *RegularPolygon {
+	return &RegularPolygon{Shape{renderer: renderer}, radius, radius, sides, radius}
+}
+
+// MoveTo - places center of the polygon.
+func (p *RegularPolygon) MoveTo(x, y float64) *RegularPolygon {
+	p.x, p.y = x, y
+	return p
+}
+
+// SetStyle - sets style of the polygon.
+func (p *RegularPolygon) SetStyle(s Style) *RegularPolygon {
+	p.style = &s
+	return p
+}
+
+// Outline - describes polygon as closed path (implements Outliner).
+func (p *RegularPolygon) Outline(r Renderer) {
+	if p.sides < 3 {
+		return
+	}
+	outlinePolygon(r, p.x, p.y, radialVertices(p.sides, p.radius, p.radius))
+}
+
+// Render - renders polygon image.
+func (p *RegularPolygon) Render() {
+	p.render(p)
+}
+
+// Star - represents star with tips pointing outwards, first one up (refined abstraction).
+type Star struct {
+	Shape
+	x, y         float64
+	points       int
+	outer, inner float64
+}
+
+// NewStar - creates new instance of a star with tips on outer radius and notches on inner radius,
+// placed so the outer circle touches both axes. Star with less than 2 points renders nothing.
+func NewStar(renderer Renderer, points int, outer, inner float64) *Star {
+	return &Star{Shape{renderer: renderer}, outer, outer, points, outer, inner}
+}
+
+// MoveTo - places center of the star.
+func (s *Star) MoveTo(x, y float64) *Star {
+	s.x, s.y = x, y
+	return s
+}
+
+// SetStyle - sets style of the star.
+func (s *Star) SetStyle(st Style) *Star {
+	s.style = &st
+	return s
+}
+
+// Outline - describes star as closed path (implements Outliner).
+func (s *Star) Outline(r Renderer) {
+	if s.points < 2 {
+		return
+	}
+	outlinePolygon(r, s.x, s.y, radialVertices(s.points*2, s.outer, s.inner))
+}
+
+// Render - renders star image.
+func (s *Star) Render() {
+	s.render(s)
+}
+
+// outlinePolygon - describes closed path through vertices offset by provided position.
+func outlinePolygon(r Renderer, x, y float64, vertices []point) {
+	for i, v := range vertices {
+		if i == 0 {
+			r.MoveTo(x+v.x, y+v.y)
+			continue
+		}
+		r.LineTo(x+v.x, y+v.y)
+	}
+	r.ClosePath()
+}
+
+// radialVertices - returns vertices evenly spread around center, first one up,
+// even vertices lie on the first radius and odd ones on the second.
+func radialVertices(n int, even, odd float64) []point {
+	vertices := make([]point, n)
+	for i := range vertices {
+		radius := even
+		if i%2 == 1 {
+			radius = odd
+		}
+		x, y := arcPoint(0, 0, radius, radius, 0, -90+360*float64(i)/float64(n))
+		// keep axis aligned vertices exact
+		vertices[i] = point{math.Round(x*1e9) / 1e9, math.Round(y*1e9) / 1e9}
+	}
+	return vertices
+}
@@ -0,0 +1,102 @@
+package bridge
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"testing"
+)
+
+// recorder - represents renderer recording primitives it was called with (implements Renderer).
+type recorder struct {
+	calls  []string
+	styles []Style      // styles of every fill
+	points []point      // points of every move and line
+	arcs   [][7]float64 // parameters of every arc
+}
+
+// MoveTo - records move (implements Renderer).
+func (r *recorder) MoveTo(x, y float64) {
+	r.calls = append(r.calls, fmt.Sprintf("M %g %g", x, y))
+	r.points = append(r.points, point{x, y})
+}
+
+// LineTo - records line (implements Renderer).
+func (r *recorder) LineTo(x, y float64) {
+	r.calls = append(r.calls, fmt.Sprintf("L %g %g", x, y))
+	r.points = append(r.points, point{x, y})
+}
+
+// Arc - records arc (implements Renderer).
+func (r *recorder) Arc(cx, cy, rx, ry, rotation, start, sweep float64) {
+	r.calls = append(r.calls, fmt.Sprintf("A %g %g %g %g %g %g %g", cx, cy, rx, ry, rotation, start, sweep))
+	r.arcs = append(r.arcs, [7]float64{cx, cy, rx, ry, rotation, start, sweep})
+}
+
+// ClosePath - records closing (implements Renderer).
+func (r *recorder) ClosePath() {
+	r.calls = append(r.calls, "Z")
+}
+
+// Fill - records painting (implements Renderer).
+func (r *recorder) Fill(style Style) {
+	r.calls = append(r.calls, "F")
+	r.styles = append(r.styles, style)
+}
+
+func TestShapesRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(r Renderer)
+		calls  []string        // primitives describing shape
+		bounds image.Rectangle // pixels painted by raster renderer
+	}{
+		{
+			"triangle", func(r Renderer) { NewTriangle(r, 0, 10, 5, 0, 10, 10).MoveTo(0, 16).Render() },
+			[]string{"M 0 26", "L 5 16", "L 10 26", "Z", "F"}, image.Rect(0, 16, 10, 26),
+		},
+		{
+			"rectangle", func(r Renderer) { NewRectangle(r, 12, 6).MoveTo(12, 18).Render() },
+			[]string{"M 12 18", "L 24 18", "L 24 24", "L 12 24", "Z", "F"}, image.Rect(12, 18, 24, 24),
+		},
+		{
+			"ellipse", func(r Renderer) { NewEllipse(r, 8, 4).Render() },
+			[]string{"A 8 4 8 4 0 0 360", "Z", "F"}, image.Rect(0, 0, 16, 8),
+		},
+		{
+			"regular polygon", func(r Renderer) { NewRegularPolygon(r, 4, 5).Render() },
+			[]string{"M 5 0", "L 10 5", "L 5 10", "L 0 5", "Z", "F"}, image.Rect(0, 0, 10, 10),
+		},
+		{
+			"star", func(r Renderer) { NewStar(r, 2, 6, 3).Render() },
+			[]string{"M 6 0", "L 9 6", "L 6 12", "L 3 6", "Z", "F"}, image.Rect(3, 0, 9, 12),
+		},
+		{"polygon with 2 sides", func(r Renderer) { NewRegularPolygon(r, 2, 5).Render() }, []string{"F"}, image.Rectangle{}},
+		{"polygon with no sides", func(r Renderer) { NewRegularPolygon(r, 0, 5).Render() }, []string{"F"}, image.Rectangle{}},
+		{"star with 1 point", func(r Renderer) { NewStar(r, 1, 6, 3).Render() }, []string{"F"}, image.Rectangle{}},
+		{"star with negative points", func(r Renderer) { NewStar(r, -3, 6, 3).Render() }, []string{"F"}, image.Rectangle{}},
+	}
+	for _, tt := range tests {
+		rec := &recorder{}
+		tt.render(rec)
+		if !reflect.DeepEqual(rec.calls, tt.calls) {
+			t.Errorf("%s: expected primitives %q, got %q", tt.name, tt.calls, rec.calls)
+		}
+
+		rr := NewRasterRenderer()
+		tt.render(rr)
+		if got := rr.Canvas().Bounds(); got != tt.bounds {
+			t.Errorf("%s: expected raster bounds %v, got %v", tt.name, tt.bounds, got)
+		}
+
+		vr := NewVectorRenderer()
+		tt.render(vr)
+		want := 1
+		if tt.bounds.Empty() {
+			want = 0
+		}
+		if got := len(vr.Document().elements); got != want {
+			t.Errorf("%s: expected %d svg elements, got %d", tt.name, want, got)
+		}
+	}
+}
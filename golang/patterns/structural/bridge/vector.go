@@ -0,0 +1,171 @@
+package bridge
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// -- Concreate Implementor
+
+// VectorRenderer - represents vector rendering (concreate implementor), emits SVG elements into document.
+type VectorRenderer struct {
+	doc  *SVGDocument
+	data []string // path data commands
+	path path     // flattened path, tracks bounds and current point
+}
+
+// NewVectorRenderer - creates new instance of VectorRenderer with empty document.
+func NewVectorRenderer() *VectorRenderer {
+	return &VectorRenderer{doc: NewSVGDocument()}
+}
+
+// Document - returns document elements are emitted into.
+func (v *VectorRenderer) Document() *SVGDocument {
+	return v.doc
+}
+
+// MoveTo - starts new subpath (implements Renderer).
+func (v *VectorRenderer) MoveTo(x, y float64) {
+	v.path.moveTo(x, y)
+	v.data = append(v.data, "M "+num(x)+" "+num(y))
+}
+
+// LineTo - adds line to current subpath (implements Renderer).
+func (v *VectorRenderer) LineTo(x, y float64) {
+	if v.current() == nil {
+		v.MoveTo(x, y)
+		return
+	}
+	v.path.lineTo(x, y)
+	v.data = append(v.data, "L "+num(x)+" "+num(y))
+}
+
+// Arc - adds elliptical arc to current subpath, joined to current point by line (implements Renderer).
+//
+// SVG arc can not start and end at the same point, so arc is emitted in pieces of at most half turn.
+func (v *VectorRenderer) Arc(cx, cy, rx, ry, rotation, start, sweep float64) {
+	x, y := arcPoint(cx, cy, rx, ry, rotation, start)
+	if cur := v.current(); cur == nil {
+		v.MoveTo(x, y)
+	} else if math.Abs(cur.x-x) > 1e-9 || math.Abs(cur.y-y) > 1e-9 {
+		v.LineTo(x, y)
+	}
+	flag := "1"
+	if sweep < 0 {
+		flag = "0"
+	}
+	pieces := int(math.Ceil(math.Abs(sweep) / 180))
+	for i := 1; i <= pieces; i++ {
+		x, y = arcPoint(cx, cy, rx, ry, rotation, start+sweep*float64(i)/float64(pieces))
+		v.data = append(v.data, fmt.Sprintf("A %s %s %s 0 %s %s %s", num(rx), num(ry), num(rotation), flag, num(x), num(y)))
+	}
+	v.path.arc(cx, cy, rx, ry, rotation, start, sweep)
+}
+
+// ClosePath - closes current subpath (implements Renderer).
+func (v *VectorRenderer) ClosePath() {
+	if v.current() == nil {
+		return
+	}
+	v.path.closePath()
+	v.data = append(v.data, "Z")
+}
+
+// Fill - emits path element and starts new path (implements Renderer).
+func (v *VectorRenderer) Fill(style Style) {
+	defer func() {
+		v.path.reset()
+		v.data = nil
+	}()
+	x0, y0, x1, y1, ok := v.path.bounds()
+	if !ok {
+		return
+	}
+	v.doc.Add(fmt.Sprintf(`<path d="%s"%s/>`, strings.Join(v.data, " "), styleAttrs(style)), x0, y0, x1, y1, style)
+}
+
+// current - returns current point, nil when there is no open subpath.
+func (v *VectorRenderer) current() *point {
+	n := len(v.path.subpaths)
+	if n == 0 || v.path.subpaths[n-1].closed {
+		return nil
+	}
+	pp := v.path.subpaths[n-1].points
+	return &pp[len(pp)-1]
+}
+
+// -- SVG Document
+
+// SVGDocument - represents SVG document collecting elements, view box grows to fit them.
+type SVGDocument struct {
+	elements               []string
+	minX, minY, maxX, maxY float64
+}
+
+// NewSVGDocument - creates new instance of empty SVGDocument.
+func NewSVGDocument() *SVGDocument {
+	return &SVGDocument{}
+}
+
+// Add - adds element covering provided area, area is widened by half of the stroke.
+func (d *SVGDocument) Add(element string, x0, y0, x1, y1 float64, style Style) {
+	half := 0.0
+	if style.Stroke != nil {
+		half = style.StrokeWidth / 2
+	}
+	d.elements = append(d.elements, element)
+	d.minX, d.minY = math.Min(d.minX, x0-half), math.Min(d.minY, y0-half)
+	d.maxX, d.maxY = math.Max(d.maxX, x1+half), math.Max(d.maxY, y1+half)
+}
+
+// WriteTo - writes document as standalone SVG (implements io.WriterTo).
+func (d *SVGDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	width, height := d.maxX-d.minX, d.maxY-d.minY
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="%s %s %s %s">`+"\n",
+		num(width), num(height), num(d.minX), num(d.minY), num(width), num(height))
+	for _, e := range d.elements {
+		fmt.Fprintf(&buf, "  %s\n", e)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.WriteTo(w)
+}
+
+// String - returns document as SVG (implements fmt.Stringer).
+func (d *SVGDocument) String() string {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.String()
+}
+
+// styleAttrs - returns presentation attributes of style.
+func styleAttrs(s Style) string {
+	attrs := ` fill="` + svgColor(s.Fill) + `"`
+	attrs += ` stroke="` + svgColor(s.Stroke) + `"`
+	if s.Stroke != nil {
+		attrs += ` stroke-width="` + num(s.StrokeWidth) + `"`
+	}
+	return attrs
+}
+
+// svgColor - returns color as SVG paint, nil is none.
+func svgColor(c color.Color) string {
+	if c == nil {
+		return "none"
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// num - formats number rounded to thousandths without trailing zeros, adding zero turns -0 into 0.
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000+0, 'f', -1, 64)
+}
@@ -0,0 +1,22 @@
+package bridge
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSVGDocumentWriteTo(t *testing.T) {
+	for _, sc := range goldenScenes {
+		r := NewVectorRenderer()
+		sc.draw(r)
+		var buf bytes.Buffer
+		n, err := r.Document().WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != int64(buf.Len()) {
+			t.Errorf("%s: expected %d bytes written, got %d", sc.name, buf.Len(), n)
+		}
+		golden(t, sc.name+".svg", buf.Bytes())
+	}
+}